@@ -0,0 +1,384 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/jira"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	BackfillAccepted = "accepted"
+	BackfillRejected = "rejected"
+	BackfillSkipped  = "skipped"
+
+	// BackfillMaxRetries is the number of times a batch is retried when Jira rate limits the request.
+	BackfillMaxRetries = 5
+	// BackfillDefaultInterval is the default minimum duration between two requests to Jira.
+	BackfillDefaultInterval = time.Second
+)
+
+// BackfillReport holds the result of each build that was processed by the backfill command.
+type BackfillReport struct {
+	BuildName        string          `json:"buildName"`
+	Accepted         int             `json:"accepted"`
+	Rejected         int             `json:"rejected"`
+	Skipped          int             `json:"skipped"`
+	Builds           []BackfillBuild `json:"builds"`
+	UnknownIssueKeys []string        `json:"unknownIssueKeys,omitempty"`
+}
+
+type BackfillBuild struct {
+	BuildNumber int64    `json:"buildNumber"`
+	Result      string   `json:"result"`
+	Reasons     []string `json:"reasons,omitempty"`
+}
+
+func (report *BackfillReport) add(buildNumber int64, result string, reasons ...string) {
+	report.Builds = append(report.Builds, BackfillBuild{BuildNumber: buildNumber, Result: result, Reasons: reasons})
+	switch result {
+	case BackfillAccepted:
+		report.Accepted++
+	case BackfillRejected:
+		report.Rejected++
+	default:
+		report.Skipped++
+	}
+}
+
+// BackfillState holds the progress of the backfill, so that an interrupted backfill can be resumed.
+type BackfillState struct {
+	BuildName       string `json:"buildName"`
+	LastBuildNumber int64  `json:"lastBuildNumber"`
+}
+
+type BackfillCommand struct {
+	buildConfiguration    *utils.BuildConfiguration
+	jiraConfiguration     *JiraConfiguration
+	backfillConfiguration *BackfillConfiguration
+	jiraService           *services.JiraService
+	lastRequest           time.Time
+	report                *BackfillReport
+}
+
+func NewBackfillCommand() *BackfillCommand {
+	return &BackfillCommand{}
+}
+
+func (cmd *BackfillCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *BackfillCommand {
+	cmd.buildConfiguration = buildConfiguration
+	return cmd
+}
+
+func (cmd *BackfillCommand) SetJiraConfiguration(jiraConfiguration *JiraConfiguration) *BackfillCommand {
+	cmd.jiraConfiguration = jiraConfiguration
+	return cmd
+}
+
+func (cmd *BackfillCommand) SetBackfillConfiguration(backfillConfiguration *BackfillConfiguration) *BackfillCommand {
+	cmd.backfillConfiguration = backfillConfiguration
+	return cmd
+}
+
+func (cmd *BackfillCommand) Run() error {
+	config := cmd.backfillConfiguration
+	buildName, err := cmd.buildConfiguration.GetBuildName()
+	if err != nil {
+		return err
+	}
+	if buildName == "" {
+		return errorutils.CheckErrorf("Missing build name")
+	}
+	cmd.report = &BackfillReport{BuildName: buildName, Builds: []BackfillBuild{}}
+
+	buildInfoService, err := services.CreateExtBuildInfoService(cmd.jiraConfiguration.serverDetails)
+	if err != nil {
+		return err
+	}
+	buildNumbers, err := cmd.getBuildNumbers(buildInfoService, buildName)
+	if err != nil {
+		return err
+	}
+	if len(buildNumbers) == 0 {
+		log.Info("Nothing to backfill, no builds of " + buildName + " found")
+		return cmd.writeReport()
+	}
+	log.Info(fmt.Sprintf("Backfilling %d builds of %s, from #%d to #%d", len(buildNumbers), buildName, buildNumbers[0],
+		buildNumbers[len(buildNumbers)-1]))
+
+	pipelinesService, err := services.NewPipelinesService(*cmd.jiraConfiguration.serverDetails)
+	if err != nil {
+		return err
+	}
+	cmd.jiraService, err = services.NewOAuthJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId,
+		cmd.jiraConfiguration.jiraSecret, cmd.jiraConfiguration.dryRun)
+	if err != nil {
+		return err
+	}
+
+	var batch []jira.BuildInfo
+	for _, buildNumber := range buildNumbers {
+		jiraBuildInfo, reason := cmd.getJiraBuildInfo(buildInfoService, pipelinesService, buildName, buildNumber)
+		if jiraBuildInfo == nil {
+			cmd.report.add(buildNumber, BackfillSkipped, reason)
+		} else {
+			batch = append(batch, *jiraBuildInfo)
+		}
+		if len(batch) == config.batchSize {
+			if err = cmd.sendBatch(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+		// Only record the progress once all builds up to the build are sent.
+		if len(batch) == 0 {
+			if err = cmd.saveState(buildName, buildNumber); err != nil {
+				return err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err = cmd.sendBatch(batch); err != nil {
+			return err
+		}
+		if err = cmd.saveState(buildName, buildNumbers[len(buildNumbers)-1]); err != nil {
+			return err
+		}
+	}
+
+	log.Info(fmt.Sprintf("Backfill of %s completed, %d builds accepted, %d rejected and %d skipped", buildName,
+		cmd.report.Accepted, cmd.report.Rejected, cmd.report.Skipped))
+	if err = cmd.writeReport(); err != nil {
+		return err
+	}
+	if cmd.report.Rejected > 0 && cmd.jiraConfiguration.failOnReject {
+		return errorutils.CheckErrorf("There are " + strconv.Itoa(cmd.report.Rejected) + " rejected builds")
+	}
+	return nil
+}
+
+// Returns the sorted numbers of the published builds to backfill, builds that were backfilled by a previous run are excluded.
+func (cmd *BackfillCommand) getBuildNumbers(buildInfoService *services.ExtBuildInfoService, buildName string) ([]int64, error) {
+	config := cmd.backfillConfiguration
+	buildRuns, err := buildInfoService.GetBuildRuns(buildName, cmd.buildConfiguration.GetProject())
+	if err != nil || buildRuns == nil {
+		return nil, err
+	}
+
+	var fromBuild, toBuild int64
+	if config.fromBuild != "" {
+		fromBuild, _ = strconv.ParseInt(config.fromBuild, 10, 64)
+	}
+	state, err := cmd.loadState(buildName)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.LastBuildNumber >= fromBuild {
+		log.Info(fmt.Sprintf("Resuming backfill of %s after build #%d", buildName, state.LastBuildNumber))
+		fromBuild = state.LastBuildNumber + 1
+	}
+	if number, err := cmd.buildConfiguration.GetBuildNumber(); err == nil && number != "" {
+		if toBuild, err = strconv.ParseInt(number, 10, 64); err != nil {
+			return nil, errorutils.CheckErrorf("Invalid build number %s", number)
+		}
+	}
+
+	var buildNumbers []int64
+	for _, build := range buildRuns.BuildsNumbers {
+		buildNumber, err := build.GetNumber()
+		if err != nil {
+			log.Debug("Excluding build "+build.Uri+" as it cannot be parsed to a build number", err)
+		} else if buildNumber >= fromBuild && (toBuild == 0 || buildNumber <= toBuild) {
+			buildNumbers = append(buildNumbers, buildNumber)
+		}
+	}
+	sort.Slice(buildNumbers, func(i, j int) bool { return buildNumbers[i] < buildNumbers[j] })
+	return buildNumbers, nil
+}
+
+// Returns the Jira build of the published build, or the reason why the build is skipped.
+func (cmd *BackfillCommand) getJiraBuildInfo(buildInfoService *services.ExtBuildInfoService, pipelinesService *services.PipelinesService,
+	buildName string, buildNumber int64) (*jira.BuildInfo, string) {
+	number := strconv.FormatInt(buildNumber, 10)
+	buildInfo, err := buildInfoService.GetBuildInfo(buildName, number, cmd.buildConfiguration.GetProject())
+	if err != nil {
+		log.Warn("Skipping build "+buildName+" #"+number+" because of error:", err)
+		return nil, err.Error()
+	} else if buildInfo == nil {
+		return nil, "build-info not found"
+	}
+	jiraBuildInfo, err := getJiraBuildInfo(&buildInfo.BuildInfo, pipelinesService, cmd.jiraConfiguration.includePrePostRunSteps,
+		buildInfo.GetState())
+	if err != nil {
+		log.Warn("Skipping build "+buildName+" #"+number+" because of error:", err)
+		return nil, err.Error()
+	} else if jiraBuildInfo == nil {
+		return nil, "no issues to send"
+	}
+	setUpdateSequenceNumber(jiraBuildInfo, getRecordedBuildUpdate(buildInfo.Properties))
+	// Historical builds are shown in Jira at the time they were started.
+	if started, err := time.Parse(buildinfo.TimeFormat, buildInfo.Started); err == nil {
+		jiraBuildInfo.LastUpdated = started
+	}
+	return jiraBuildInfo, ""
+}
+
+// Sends the batch of builds to Jira, waiting for the configured interval between requests, and retrying the batch if Jira rate
+// limits the request.
+func (cmd *BackfillCommand) sendBatch(batch []jira.BuildInfo) error {
+	log.Info(fmt.Sprintf("Sending %d builds to Jira, #%d to #%d", len(batch), batch[0].BuildNumber, batch[len(batch)-1].BuildNumber))
+	for attempt := 0; ; attempt++ {
+		if wait := cmd.backfillConfiguration.interval - time.Since(cmd.lastRequest); wait > 0 {
+			time.Sleep(wait)
+		}
+		cmd.lastRequest = time.Now()
+		response, err := cmd.jiraService.SendBuildInfos(batch)
+		var rateLimitError *services.RateLimitError
+		if errors.As(err, &rateLimitError) && attempt < BackfillMaxRetries {
+			log.Warn(rateLimitError.Error())
+			time.Sleep(rateLimitError.RetryAfter)
+			continue
+		} else if err != nil {
+			return err
+		}
+		logBuildInfoResponse(response)
+		for _, build := range response.AcceptedBuilds {
+			cmd.report.add(build.BuildNumber, BackfillAccepted)
+		}
+		for _, build := range response.RejectedBuilds {
+			var reasons []string
+			for _, buildError := range build.Errors {
+				reasons = append(reasons, buildError.Message)
+			}
+			cmd.report.add(build.Key.BuildNumber, BackfillRejected, reasons...)
+		}
+		cmd.report.UnknownIssueKeys = append(cmd.report.UnknownIssueKeys, response.UnknownIssueKeys...)
+		return nil
+	}
+}
+
+// Returns the state of a previous backfill of the build, or nil if there is no previous backfill.
+func (cmd *BackfillCommand) loadState(buildName string) (*BackfillState, error) {
+	resumeFile := cmd.backfillConfiguration.resumeFile
+	if resumeFile == "" {
+		return nil, nil
+	}
+	exists, err := fileutils.IsFileExists(resumeFile, false)
+	if err != nil || !exists {
+		return nil, err
+	}
+	content, err := os.ReadFile(resumeFile)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	state := &BackfillState{}
+	if err = json.Unmarshal(content, state); err != nil {
+		return nil, errorutils.CheckErrorf("Unable to read the backfill state from %s: %s", resumeFile, err.Error())
+	}
+	if state.BuildName != buildName {
+		log.Warn("Ignoring the backfill state in " + resumeFile + ", it is the state of build " + state.BuildName)
+		return nil, nil
+	}
+	return state, nil
+}
+
+func (cmd *BackfillCommand) saveState(buildName string, lastBuildNumber int64) error {
+	resumeFile := cmd.backfillConfiguration.resumeFile
+	if resumeFile == "" || cmd.jiraConfiguration.dryRun {
+		return nil
+	}
+	content, err := json.Marshal(BackfillState{BuildName: buildName, LastBuildNumber: lastBuildNumber})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.WriteFile(resumeFile, content, 0644))
+}
+
+func (cmd *BackfillCommand) writeReport() error {
+	output := cmd.backfillConfiguration.output
+	if output == "" {
+		return nil
+	}
+	sort.SliceStable(cmd.report.Builds, func(i, j int) bool {
+		return cmd.report.Builds[i].BuildNumber < cmd.report.Builds[j].BuildNumber
+	})
+	content, err := json.MarshalIndent(cmd.report, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if output == "-" {
+		_, err = fmt.Println(string(content))
+		return errorutils.CheckError(err)
+	}
+	log.Info("Writing the backfill report to " + output)
+	return errorutils.CheckError(os.WriteFile(output, content, 0644))
+}
+
+type BackfillConfiguration struct {
+	fromBuild  string
+	batchSize  int
+	interval   time.Duration
+	resumeFile string
+	output     string
+}
+
+// SetFromBuild sets the number of the first build to backfill, defaults to the first published build.
+func (bc *BackfillConfiguration) SetFromBuild(fromBuild string) *BackfillConfiguration {
+	bc.fromBuild = fromBuild
+	return bc
+}
+
+// SetBatchSize sets the number of builds sent to Jira per request, defaults to the maximum of services.JiraBuildsBatchSize.
+func (bc *BackfillConfiguration) SetBatchSize(batchSize int) *BackfillConfiguration {
+	bc.batchSize = batchSize
+	return bc
+}
+
+// SetInterval sets the minimum duration between two requests to Jira.
+func (bc *BackfillConfiguration) SetInterval(interval time.Duration) *BackfillConfiguration {
+	bc.interval = interval
+	return bc
+}
+
+// SetResumeFile sets the file that records the progress of the backfill, a backfill resumes after the last recorded build.
+func (bc *BackfillConfiguration) SetResumeFile(resumeFile string) *BackfillConfiguration {
+	bc.resumeFile = resumeFile
+	return bc
+}
+
+// SetOutput sets the file to write the report to, or - to write it to stdout.
+func (bc *BackfillConfiguration) SetOutput(output string) *BackfillConfiguration {
+	bc.output = output
+	return bc
+}
+
+func (bc *BackfillConfiguration) ValidateBackfillConfiguration() error {
+	if bc.fromBuild != "" {
+		if _, err := strconv.ParseInt(bc.fromBuild, 10, 64); err != nil {
+			return errorutils.CheckErrorf("Invalid from-build %s, it must be a build number", bc.fromBuild)
+		}
+	}
+	if bc.batchSize == 0 {
+		bc.batchSize = services.JiraBuildsBatchSize
+	}
+	if bc.batchSize < 1 || bc.batchSize > services.JiraBuildsBatchSize {
+		return errorutils.CheckErrorf("Invalid batch-size %d, it must be between 1 and %d", bc.batchSize, services.JiraBuildsBatchSize)
+	}
+	if bc.interval == 0 {
+		bc.interval = BackfillDefaultInterval
+	}
+	if bc.interval < 0 {
+		return errorutils.CheckErrorf("Invalid interval %s, it cannot be negative", bc.interval)
+	}
+	return nil
+}
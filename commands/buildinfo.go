@@ -5,6 +5,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	utilsconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	artservices "github.com/jfrog/jfrog-client-go/artifactory/services"
+	"sort"
 )
 
 // Returns build info, or empty build info struct if not found.
@@ -26,7 +27,8 @@ func getBuildInfo(buildConfig *utils.BuildConfiguration, serverDetails *utilscon
 
 	bis := artservices.NewBuildInfoService(sm.GetConfig().GetServiceDetails(), sm.Client())
 
-	publishedBuildInfo, found, err := bis.GetBuildInfo(artservices.BuildInfoParams{BuildName: buildName, BuildNumber: buildNumber})
+	publishedBuildInfo, found, err := bis.GetBuildInfo(artservices.BuildInfoParams{BuildName: buildName, BuildNumber: buildNumber,
+		ProjectKey: buildConfig.GetProject()})
 	if err != nil {
 		return nil, err
 	} else if !found {
@@ -35,3 +37,41 @@ func getBuildInfo(buildConfig *utils.BuildConfiguration, serverDetails *utilscon
 		return &publishedBuildInfo.BuildInfo, nil
 	}
 }
+
+// Returns the build-info of the build that is not published yet, assembled from the partials in the local build directory.
+func getLocalBuildInfo(buildConfig *utils.BuildConfiguration) (*buildinfo.BuildInfo, error) {
+	buildName, err := buildConfig.GetBuildName()
+	if err != nil {
+		return nil, err
+	}
+	buildNumber, err := buildConfig.GetBuildNumber()
+	if err != nil {
+		return nil, err
+	}
+	partials, err := utils.ReadPartialBuildInfoFiles(buildName, buildNumber, buildConfig.GetProject())
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(partials)
+
+	buildInfo := &buildinfo.BuildInfo{Name: buildName, Number: buildNumber, Properties: map[string]string{}}
+	issues := map[string]bool{}
+	for _, partial := range partials {
+		if partial.Issues != nil {
+			if buildInfo.Issues == nil {
+				buildInfo.Issues = &buildinfo.Issues{}
+			}
+			for _, issue := range partial.Issues.AffectedIssues {
+				if !issues[issue.Key] {
+					issues[issue.Key] = true
+					buildInfo.Issues.AffectedIssues = append(buildInfo.Issues.AffectedIssues, issue)
+				}
+			}
+		}
+		buildInfo.VcsList = append(buildInfo.VcsList, partial.VcsList...)
+		for key, value := range partial.Env {
+			buildInfo.Properties[key] = value
+		}
+	}
+	return buildInfo, nil
+}
@@ -1,13 +1,14 @@
 package commands
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
 	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/ci"
+	"github.com/marvelution/ext-build-info/services/tracker"
 	"github.com/marvelution/ext-build-info/util"
-	"io"
 	"os"
-	"os/exec"
-	"strconv"
+	"path/filepath"
 	"strings"
 
 	buildinfo "github.com/jfrog/build-info-go/entities"
@@ -15,8 +16,6 @@ import (
 	utilsconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	artservices "github.com/jfrog/jfrog-client-go/artifactory/services"
 	artclientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
-	clientutils "github.com/jfrog/jfrog-client-go/utils"
-	gofrogcmd "github.com/marvelution/ext-build-info/io"
 
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
@@ -24,14 +23,66 @@ import (
 )
 
 const (
-	GitLogLimit   = 100
-	IssueKeyRegex = "(((?:\\p{Lu}[\\p{Lu}\\p{N}_]+|\\p{Ll}[\\p{Ll}\\p{N}_]+))-\\p{N}+)"
+	GitLogLimit       = 100
+	MaxPreviousBuilds = 100
+
+	BaselineLatest = "LATEST"
+	BaselineBranch = "BRANCH"
+	BaselineStatus = "STATUS"
+
+	RevisionStrategyRange         = "range"
+	RevisionStrategyMergeBase     = "merge-base"
+	RevisionStrategyAncestorBuild = "ancestor-build"
+	RevisionStrategyHead          = "head"
+
+	IssuesPropertyPrefix = "buildInfo.issues."
+	CiPropertyPrefix     = "buildInfo.ci."
 )
 
 type CollectIssueCommand struct {
 	buildConfiguration  *utils.BuildConfiguration
-	dotGitPath          string
+	dotGitPaths         []string
+	includeSubmodules   bool
 	issuesConfiguration *IssuesConfiguration
+	output              string
+	properties          map[string]string
+	result              *CollectIssuesResult
+}
+
+// CollectIssuesResult is the machine-readable result of collect-issues, written as JSON to the output.
+type CollectIssuesResult struct {
+	BuildName    string                `json:"buildName"`
+	BuildNumber  string                `json:"buildNumber"`
+	Tracker      string                `json:"tracker,omitempty"`
+	Repositories []CollectedRepository `json:"repositories"`
+	Issues       []CollectedIssue      `json:"issues"`
+}
+
+type CollectedRepository struct {
+	Path             string            `json:"path"`
+	Url              string            `json:"url,omitempty"`
+	Branch           string            `json:"branch,omitempty"`
+	Revision         string            `json:"revision"`
+	PreviousRevision string            `json:"previousRevision,omitempty"`
+	RevisionRange    string            `json:"revisionRange,omitempty"`
+	RevisionStrategy string            `json:"revisionStrategy,omitempty"`
+	Commits          []CollectedCommit `json:"commits"`
+}
+
+type CollectedCommit struct {
+	Hash      string   `json:"hash"`
+	Subject   string   `json:"subject"`
+	IssueKeys []string `json:"issueKeys,omitempty"`
+}
+
+type CollectedIssue struct {
+	Key          string            `json:"key"`
+	Summary      string            `json:"summary,omitempty"`
+	Url          string            `json:"url,omitempty"`
+	Aggregated   bool              `json:"aggregated,omitempty"`
+	Relationship string            `json:"relationship,omitempty"`
+	Sources      []string          `json:"sources,omitempty"`
+	Fields       map[string]string `json:"fields,omitempty"`
 }
 
 func NewCollectIssueCommand() *CollectIssueCommand {
@@ -43,8 +94,19 @@ func (cmd *CollectIssueCommand) SetBuildConfiguration(buildConfiguration *utils.
 	return cmd
 }
 
-func (cmd *CollectIssueCommand) SetDotGitPath(dotGitPath string) *CollectIssueCommand {
-	cmd.dotGitPath = dotGitPath
+func (cmd *CollectIssueCommand) SetDotGitPaths(dotGitPaths ...string) *CollectIssueCommand {
+	cmd.dotGitPaths = dotGitPaths
+	return cmd
+}
+
+func (cmd *CollectIssueCommand) SetIncludeSubmodules(includeSubmodules bool) *CollectIssueCommand {
+	cmd.includeSubmodules = includeSubmodules
+	return cmd
+}
+
+// SetOutput sets the file to write the collected issues to as JSON, use - to write to stdout.
+func (cmd *CollectIssueCommand) SetOutput(output string) *CollectIssueCommand {
+	cmd.output = output
 	return cmd
 }
 
@@ -71,58 +133,134 @@ func (cmd *CollectIssueCommand) Run() error {
 	}
 
 	// Find .git if it wasn't provided in the command.
-	if cmd.dotGitPath == "" {
-		var exists bool
-		cmd.dotGitPath, exists, err = fileutils.FindUpstream(".git", fileutils.Any)
+	if len(cmd.dotGitPaths) == 0 {
+		dotGitPath, exists, err := fileutils.FindUpstream(".git", fileutils.Any)
 		if err != nil {
 			return err
 		}
 		if !exists {
 			return errorutils.CheckErrorf("Could not find .git")
 		}
+		cmd.dotGitPaths = []string{dotGitPath}
 	}
 
-	// Collect URL, branch and revision into GitManager.
-	gitManager := clientutils.NewGitManager(cmd.dotGitPath)
-	err = gitManager.ReadConfig()
-	if err != nil {
-		return err
+	// Open the git repositories, including their submodules if required.
+	var repositories []*util.GitRepository
+	for _, dotGitPath := range cmd.dotGitPaths {
+		repository, err := util.OpenGitRepository(dotGitPath)
+		if err != nil {
+			return err
+		}
+		repositories = append(repositories, repository)
+		if cmd.includeSubmodules {
+			submodules, err := repository.GetSubmodules()
+			if err != nil {
+				return err
+			}
+			repositories = append(repositories, submodules...)
+		}
 	}
 
-	vcs := buildinfo.Vcs{
-		Url:      gitManager.GetUrl(),
-		Revision: gitManager.GetRevision(),
-		Branch:   gitManager.GetBranch(),
-		Message:  gitManager.GetMessage(),
+	cmd.properties = map[string]string{}
+	cmd.result = &CollectIssuesResult{BuildName: buildName, BuildNumber: buildNumber, Repositories: []CollectedRepository{},
+		Issues: []CollectedIssue{}}
+	if pathFilter := cmd.issuesConfiguration.pathFilter; cmd.issuesConfiguration.tracker != nil && !pathFilter.IsEmpty() {
+		if len(pathFilter.Include) > 0 {
+			cmd.properties[IssuesPropertyPrefix+"includePaths"] = strings.Join(pathFilter.Include, ",")
+		}
+		if len(pathFilter.Exclude) > 0 {
+			cmd.properties[IssuesPropertyPrefix+"excludePaths"] = strings.Join(pathFilter.Exclude, ",")
+		}
 	}
-	if vcs.Branch == "" {
-		for _, e := range os.Environ() {
-			pair := strings.SplitN(e, "=", 2)
-			if pair[1] == vcs.Revision {
-				branchVariableName := strings.TrimSuffix(pair[0], "commitSha") + "branchName"
-				vcs.Branch = os.Getenv(branchVariableName)
-				log.Info("Found git branch name '" + vcs.Branch + "' in environment variable: " + branchVariableName)
+
+	// Record the details of the run of the CI system that builds the build.
+	if environment := ci.Detect(); environment.Provider != "" {
+		for name, value := range map[string]string{"provider": environment.Provider, "runNumber": environment.RunNumber,
+			"runUrl": environment.RunUrl, "pullRequest": environment.PullRequest} {
+			if value != "" {
+				cmd.properties[CiPropertyPrefix+name] = value
+			}
+		}
+	}
+
+	// Collect URL, branch and revision from each git repository.
+	var vcsList []buildinfo.Vcs
+	for _, repository := range repositories {
+		vcs, err := cmd.getVcs(repository)
+		if err != nil {
+			return err
+		}
+		vcsList = append(vcsList, vcs)
+	}
+
+	// Collect issue references from each git repository since the revision of the baseline build, if required.
+	var issueReferences []IssueReference
+	if cmd.issuesConfiguration.tracker != nil {
+		log.Debug("Collecting issues hosted on ", cmd.issuesConfiguration.tracker.Name())
+		cmd.result.Tracker = cmd.issuesConfiguration.tracker.Name()
+		if err = cmd.issuesConfiguration.discoverProjects(); err != nil {
+			return err
+		}
+		previousRevisions, err := cmd.getPreviousVcsRevisions(vcsList)
+		if err != nil {
+			return err
+		}
+		for i, repository := range repositories {
+			references, err := cmd.collectBuildIssueReferences(repository, vcsList[i], previousRevisions[i])
+			if err != nil {
+				return err
 			}
+			issueReferences = append(issueReferences, references...)
+		}
+	} else {
+		for i, repository := range repositories {
+			cmd.result.Repositories = append(cmd.result.Repositories, CollectedRepository{Path: repository.GetPath(), Url: vcsList[i].Url,
+				Branch: vcsList[i].Branch, Revision: vcsList[i].Revision, Commits: []CollectedCommit{}})
 		}
 	}
 
-	// Collect issues if required.
+	// Resolve the issues of all repositories at once.
 	var issues []buildinfo.AffectedIssue
 	if cmd.issuesConfiguration.tracker != nil {
-		log.Debug("Collecting issues hosted on ", cmd.issuesConfiguration.tracker.Name)
-		issues, err = cmd.collectBuildIssues(vcs)
+		var issueKeys []string
+		for _, reference := range issueReferences {
+			issueKeys = append(issueKeys, reference.Key)
+		}
+		issueKeys = util.RemoveDuplicate(issueKeys)
+		if len(issueKeys) > 0 {
+			issues, err = cmd.resolveIssues(issueKeys)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Record how each issue relates to the build.
+		relationships := getIssueRelationships(issueReferences)
+		for _, issue := range issues {
+			if relationship, found := relationships[issue.Key]; found {
+				cmd.properties[IssuesPropertyPrefix+issue.Key+".relationship"] = relationship.Relationship
+				cmd.properties[IssuesPropertyPrefix+issue.Key+".sources"] = strings.Join(relationship.Sources, ",")
+			}
+		}
+	}
+
+	// Carry over the issues of previous builds, if required.
+	var aggregatedIssues []buildinfo.AffectedIssue
+	if cmd.issuesConfiguration.tracker != nil && cmd.issuesConfiguration.aggregate {
+		aggregatedIssues, err = cmd.getAggregatedIssues(issues, vcsList)
 		if err != nil {
 			return err
 		}
+		issues = append(issues, aggregatedIssues...)
 	}
 
 	// Populate partials with VCS info.
 	populateFunc := func(partial *buildinfo.Partial) {
-		partial.VcsList = append(partial.VcsList, vcs)
+		partial.VcsList = append(partial.VcsList, vcsList...)
 
 		if cmd.issuesConfiguration.tracker != nil {
 			partial.Issues = &buildinfo.Issues{
-				Tracker:                &buildinfo.Tracker{Name: cmd.issuesConfiguration.tracker.Name, Version: ""},
+				Tracker:                &buildinfo.Tracker{Name: cmd.issuesConfiguration.tracker.Name(), Version: ""},
 				AggregateBuildIssues:   cmd.issuesConfiguration.aggregate,
 				AggregationBuildStatus: cmd.issuesConfiguration.aggregationStatus,
 				AffectedIssues:         issues,
@@ -134,224 +272,441 @@ func (cmd *CollectIssueCommand) Run() error {
 		return err
 	}
 
+	// Populate a separate partial with the properties, since the env of a partial with VCS info is ignored.
+	if len(cmd.properties) > 0 {
+		err = utils.SavePartialBuildInfo(buildName, buildNumber, cmd.buildConfiguration.GetProject(), func(partial *buildinfo.Partial) {
+			partial.Env = cmd.properties
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.output != "" {
+		if err = cmd.writeResult(issues); err != nil {
+			return err
+		}
+	}
+
 	// Done.
-	log.Info("Collected", len(issues), "issue details for", buildName+"/"+buildNumber+".")
+	log.Info("Collected", len(issues)-len(aggregatedIssues), "issue details, and aggregated", len(aggregatedIssues),
+		"issue details from previous builds, for", buildName+"/"+buildNumber+".")
 	return nil
 }
 
-func (cmd *CollectIssueCommand) collectBuildIssues(vcs buildinfo.Vcs) ([]buildinfo.AffectedIssue, error) {
-	log.Info("Collecting build issues from VCS...")
-
-	// Check that git exists in path.
-	_, err := exec.LookPath("git")
+// Writes the result, including the collected issues, as JSON to the output file or stdout.
+func (cmd *CollectIssueCommand) writeResult(issues []buildinfo.AffectedIssue) error {
+	for _, issue := range issues {
+		relationship := getIssueRelationship(cmd.properties, issue.Key)
+		collected := CollectedIssue{Key: issue.Key, Summary: issue.Summary, Url: issue.Url, Aggregated: issue.Aggregated,
+			Relationship: relationship.Relationship, Sources: relationship.Sources}
+		if fields := getIssueFields(cmd.properties, issue.Key); len(fields) > 0 {
+			collected.Fields = fields
+		}
+		cmd.result.Issues = append(cmd.result.Issues, collected)
+	}
+	content, err := json.MarshalIndent(cmd.result, "", "  ")
 	if err != nil {
-		return nil, errorutils.CheckError(err)
+		return errorutils.CheckError(err)
+	}
+	if cmd.output == "-" {
+		_, err = fmt.Println(string(content))
+		return errorutils.CheckError(err)
 	}
+	log.Info("Writing the collected issues to " + cmd.output)
+	return errorutils.CheckError(os.WriteFile(cmd.output, content, 0644))
+}
 
-	// Get latest build's VCS revision from Artifactory.
-	lastVcsRevision, err := cmd.getLatestVcsRevision(vcs.Url)
+// Resolves the issue keys into affected issues, the requested fields of the issues are recorded as build-info properties.
+func (cmd *CollectIssueCommand) resolveIssues(issueKeys []string) ([]buildinfo.AffectedIssue, error) {
+	issuesConfig := cmd.issuesConfiguration
+	if len(issuesConfig.fields) == 0 {
+		return issuesConfig.tracker.Resolve(issueKeys)
+	}
+	issues, fieldValues, err := issuesConfig.tracker.(tracker.FieldResolver).ResolveFields(issueKeys, issuesConfig.fields)
 	if err != nil {
 		return nil, err
 	}
+	for key, values := range fieldValues {
+		for field, value := range values {
+			if value != "" {
+				cmd.properties[IssuesPropertyPrefix+key+"."+field] = value
+			}
+		}
+	}
+	return issues, nil
+}
 
-	// Run issues collection.
-	return cmd.DoCollect(cmd.issuesConfiguration, buildinfo.Vcs{Revision: lastVcsRevision, Branch: vcs.Branch, Message: vcs.Message})
+// Returns the issues of previous builds of the same branch, up to the latest build with the aggregation status, marked as
+// aggregated. Issues that are already in the collected issues are left out.
+func (cmd *CollectIssueCommand) getAggregatedIssues(collectedIssues []buildinfo.AffectedIssue, vcsList []buildinfo.Vcs) ([]buildinfo.AffectedIssue, error) {
+	seenKeys := map[string]bool{}
+	for _, issue := range collectedIssues {
+		seenKeys[issue.Key] = true
+	}
+
+	var aggregatedIssues []buildinfo.AffectedIssue
+	aggregationStatus := cmd.issuesConfiguration.aggregationStatus
+	err := cmd.walkPreviousBuildInfos(func(buildInfo *services.ExtBuildInfo) (bool, error) {
+		if aggregationStatus != "" && buildInfo.HasStatus(aggregationStatus) {
+			log.Debug("Stopped aggregating issues at build "+buildInfo.Number+" with status", aggregationStatus)
+			return false, nil
+		}
+		if buildInfo.Issues == nil || !isSameBranch(&buildInfo.BuildInfo, vcsList) {
+			return true, nil
+		}
+		for _, issue := range buildInfo.Issues.AffectedIssues {
+			if !seenKeys[issue.Key] {
+				log.Debug("Aggregating issue " + issue.Key + " from build " + buildInfo.Number)
+				seenKeys[issue.Key] = true
+				issue.Aggregated = true
+				aggregatedIssues = append(aggregatedIssues, issue)
+			}
+		}
+		return true, nil
+	})
+	return aggregatedIssues, err
+}
+
+// Walks the build-infos of previous builds, starting with the newest build, up to the maximum number of previous builds.
+func (cmd *CollectIssueCommand) walkPreviousBuildInfos(walkFunc func(buildInfo *services.ExtBuildInfo) (bool, error)) error {
+	buildInfoService, err := services.CreateExtBuildInfoService(cmd.issuesConfiguration.serverDetails)
+	if err != nil {
+		return err
+	}
+	maxPreviousBuilds := cmd.issuesConfiguration.maxPreviousBuilds
+	walked := 0
+	return buildInfoService.WalkBuildInfos(cmd.buildConfiguration, func(buildInfo *services.ExtBuildInfo) (bool, error) {
+		if proceed, err := walkFunc(buildInfo); err != nil || !proceed {
+			return false, err
+		}
+		if walked++; walked >= maxPreviousBuilds {
+			log.Debug("Stopped reading previous builds at build "+buildInfo.Number+", the maximum of", maxPreviousBuilds,
+				"previous builds is reached")
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// Returns true if the build-info is of the same branch as the vcs list, for each repository that is in both. Build-infos without
+// any of the repositories, or vcs lists without branches, are considered to be of the same branch.
+func isSameBranch(buildInfo *buildinfo.BuildInfo, vcsList []buildinfo.Vcs) bool {
+	for _, vcs := range vcsList {
+		if previousVcs := findVcs(buildInfo, vcs.Url); previousVcs != nil && vcs.Branch != "" && previousVcs.Branch != vcs.Branch {
+			return false
+		}
+	}
+	return true
 }
 
-func (cmd *CollectIssueCommand) DoCollect(issuesConfig *IssuesConfiguration, vcs buildinfo.Vcs) ([]buildinfo.AffectedIssue, error) {
-	var foundIssueKeys []string
-	logRegExp, err := createLogRegExpHandler(issuesConfig, &foundIssueKeys)
+// Returns the vcs details of the repository, the branch is looked up in the CI environment if HEAD is detached.
+func (cmd *CollectIssueCommand) getVcs(repository *util.GitRepository) (buildinfo.Vcs, error) {
+	vcs, err := repository.GetVcs()
+	if err != nil {
+		return vcs, err
+	}
+	if vcs.Branch == "" {
+		environment := ci.Detect()
+		if vcs.Branch = environment.GetBranch(vcs.Revision); vcs.Branch != "" {
+			log.Info("Found git branch name '" + vcs.Branch + "' in the environment of " + environment.Provider)
+		}
+	}
+	return vcs, nil
+}
+
+func (cmd *CollectIssueCommand) collectBuildIssueReferences(repository *util.GitRepository, vcs buildinfo.Vcs, lastVcsRevision string) ([]IssueReference, error) {
+	log.Info("Collecting build issues from VCS " + repository.GetPath() + "...")
+
+	// Make sure the previous revision can be used to determine the range of new commits.
+	revision, strategy, err := cmd.getRevisionStrategy(repository, vcs, lastVcsRevision)
 	if err != nil {
 		return nil, err
 	}
+	log.Debug("Using revision strategy " + strategy + " with revision " + revision + " for VCS " + repository.GetPath())
+	propertyPrefix := IssuesPropertyPrefix + getRepositoryName(repository, vcs) + "."
+	cmd.properties[propertyPrefix+"revisionStrategy"] = strategy
+	if revision != "" {
+		cmd.properties[propertyPrefix+"previousRevision"] = revision
+	}
 
-	errRegExp, err := createErrRegExpHandler(vcs.Revision)
+	// Run issues collection.
+	references, commits, err := cmd.DoCollect(cmd.issuesConfiguration, repository, buildinfo.Vcs{Url: vcs.Url, Revision: revision,
+		Branch: vcs.Branch, Message: vcs.Message})
 	if err != nil {
 		return nil, err
 	}
 
-	// Get log with limit, starting from the latest commit.
-	var logLimit int
-	if len(vcs.Revision) > 0 {
-		logLimit = issuesConfig.logLimit
-	} else {
-		logLimit = 1
+	collected := CollectedRepository{Path: repository.GetPath(), Url: vcs.Url, Branch: vcs.Branch, Revision: vcs.Revision,
+		PreviousRevision: revision, RevisionStrategy: strategy, Commits: []CollectedCommit{}}
+	if revision != "" {
+		collected.RevisionRange = revision + ".." + vcs.Revision
 	}
-	logCmd := &LogCmd{logLimit: logLimit, lastVcsRevision: vcs.Revision}
+	for _, commit := range commits {
+		var issueKeys []string
+		for _, reference := range references {
+			if reference.Commit == commit.Hash {
+				issueKeys = append(issueKeys, reference.Key)
+			}
+		}
+		collected.Commits = append(collected.Commits, CollectedCommit{Hash: commit.Hash, Subject: commit.GetSubject(),
+			IssueKeys: util.RemoveDuplicate(issueKeys)})
+	}
+	cmd.result.Repositories = append(cmd.result.Repositories, collected)
+	return references, nil
+}
 
-	// Change working dir to where .git is.
-	wd, err := os.Getwd()
-	if errorutils.CheckError(err) != nil {
-		return nil, err
+// Returns the revision to collect issues since, together with the strategy used to determine the revision.
+// If the previous revision is no longer in the history of HEAD, e.g. after a force-push or squash, then the merge-base of the
+// previous revision and HEAD is used if available, or otherwise the newest revision of earlier builds that is in the history.
+func (cmd *CollectIssueCommand) getRevisionStrategy(repository *util.GitRepository, vcs buildinfo.Vcs, lastVcsRevision string) (string, string, error) {
+	if lastVcsRevision == "" {
+		return "", RevisionStrategyHead, nil
 	}
-	defer os.Chdir(wd)
-	err = os.Chdir(cmd.dotGitPath)
-	if errorutils.CheckError(err) != nil {
-		return nil, err
+	if repository.IsAncestorOfHead(lastVcsRevision) {
+		return lastVcsRevision, RevisionStrategyRange, nil
+	}
+	log.Info("Revision " + lastVcsRevision + " of the previous build is not in the history of HEAD, looking for an alternative")
+
+	if mergeBase := repository.GetMergeBase(lastVcsRevision); mergeBase != "" {
+		log.Info("Using the merge-base " + mergeBase + " of " + lastVcsRevision + " and HEAD")
+		return mergeBase, RevisionStrategyMergeBase, nil
+	}
+
+	ancestorRevision := ""
+	err := cmd.walkPreviousBuildInfos(func(buildInfo *services.ExtBuildInfo) (bool, error) {
+		revision := getPreviousVcsRevision(&buildInfo.BuildInfo, vcs.Url)
+		if revision != "" && revision != lastVcsRevision && repository.IsAncestorOfHead(revision) {
+			log.Info("Using revision " + revision + " of build " + buildInfo.Number)
+			ancestorRevision = revision
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if ancestorRevision != "" {
+		return ancestorRevision, RevisionStrategyAncestorBuild, nil
+	}
+
+	log.Info("No revision of earlier builds is in the history of HEAD. Only the latest commit is used to collect issues.")
+	return "", RevisionStrategyHead, nil
+}
+
+// Returns the name of the repository to use in build-info properties, this is the path of the vcs url if available.
+func getRepositoryName(repository *util.GitRepository, vcs buildinfo.Vcs) string {
+	if name := util.GetVcsRepositoryPath(vcs.Url); name != "" {
+		return name
 	}
+	return filepath.Base(repository.GetPath())
+}
 
-	// Run git command.
-	_, _, exitOk, err := gofrogcmd.RunCmdWithOutputParser(logCmd, false, logRegExp, errRegExp)
+// DoCollect returns the normalized issue references found in the git log since the vcs revision, the branch name and the commit
+// message, together with the commits that were scanned.
+func (cmd *CollectIssueCommand) DoCollect(issuesConfig *IssuesConfiguration, repository *util.GitRepository, vcs buildinfo.Vcs) ([]IssueReference, []util.GitCommit, error) {
+	// The paths of the filter are relative to the root of the superproject, so commits of submodules are not filtered.
+	var pathFilter *util.PathFilter
+	if !repository.IsSubmodule() {
+		pathFilter = &issuesConfig.pathFilter
+	}
+	headMatches, err := repository.HeadMatches(pathFilter)
 	if err != nil {
-		if _, ok := err.(RevisionRangeError); ok {
+		return nil, nil, err
+	}
+
+	// Get log with limit, starting from the latest commit. Without a revision only the latest commit is used, provided it
+	// changes the paths of the filter.
+	var commits []util.GitCommit
+	if len(vcs.Revision) > 0 {
+		commits, err = repository.GetCommits(vcs.Revision, issuesConfig.logLimit, pathFilter)
+	} else if headMatches {
+		commits, err = repository.GetCommits("", 1, nil)
+	}
+	if err != nil {
+		if _, ok := err.(util.RevisionRangeError); ok {
 			if len(vcs.Revision) > 0 {
-				return cmd.DoCollect(cmd.issuesConfiguration, buildinfo.Vcs{Revision: "", Branch: vcs.Branch, Message: vcs.Message})
+				return cmd.DoCollect(issuesConfig, repository, buildinfo.Vcs{Url: vcs.Url, Revision: "", Branch: vcs.Branch, Message: vcs.Message})
 			} else {
 				// Revision not found in range. Ignore and don't collect new issues.
 				log.Info(err.Error())
-				return []buildinfo.AffectedIssue{}, nil
+				return []IssueReference{}, nil, nil
 			}
 		}
-		return nil, errorutils.CheckError(err)
-	}
-	if !exitOk {
-		// May happen when trying to run git log for non-existing revision.
-		return nil, errorutils.CheckErrorf("failed executing git log command")
+		return nil, nil, err
 	}
 
-	issueRegexp, err := clientutils.GetRegExp(issuesConfig.regexp)
-	if err != nil {
-		return nil, err
+	var foundReferences []IssueReference
+	for _, commit := range commits {
+		// Look at git log and commit trailers for issue keys
+		found := findIssueReferences(issuesConfig.messagePatterns, commit.GetLogLine(), SourceCommitMessage)
+		found = append(found, findTrailerIssueReferences(issuesConfig.messagePatterns, commit.GetTrailers())...)
+		for i := range found {
+			found[i].Commit = commit.Hash
+		}
+		if len(found) > 0 {
+			log.Debug("Found issues in commit log: ", found)
+			foundReferences = append(foundReferences, found...)
+		}
 	}
-	if len(vcs.Branch) > 0 {
+	// When filtering paths, the branch and commit message only count if the commits touch the paths.
+	if len(vcs.Branch) > 0 && (len(commits) > 0 || pathFilter.IsEmpty()) {
 		// Look at git branch for issue keys
-		matchedResults := issueRegexp.FindAllStringSubmatch(vcs.Branch, -1)
-		for _, matches := range matchedResults {
-			if len(matches)-1 < issuesConfig.keyGroupIndex {
-				return nil, errors.New("unexpected result while parsing issues from git branch. " +
-					"Make sure that the regular expression used to find issues, includes a capturing group, for the issue ID")
-			}
-			found := matches[issuesConfig.keyGroupIndex]
-			log.Debug("Found issues in branch name: ", found)
-			foundIssueKeys = append(foundIssueKeys, found)
+		for _, reference := range findIssueReferences(issuesConfig.branchPatterns, vcs.Branch, SourceBranch) {
+			log.Debug("Found issues in branch name: ", reference.Key)
+			// Keywords in branch names, like fix/ABC-1, don't imply a relationship.
+			reference.Relationship = RelationshipMentioned
+			foundReferences = append(foundReferences, reference)
 		}
 	}
-	if len(vcs.Message) > 0 {
+	if len(vcs.Message) > 0 && headMatches {
 		// Look at git commit message for issue keys
-		matchedResults := issueRegexp.FindAllStringSubmatch(vcs.Message, -1)
-		for _, matches := range matchedResults {
-			if len(matches)-1 < issuesConfig.keyGroupIndex {
-				return nil, errors.New("unexpected result while parsing issues from git commit message. " +
-					"Make sure that the regular expression used to find issues, includes a capturing group, for the issue ID")
-			}
-			found := matches[issuesConfig.keyGroupIndex]
-			log.Debug("Found issues in last commit message: ", found)
-			foundIssueKeys = append(foundIssueKeys, found)
+		found := findIssueReferences(issuesConfig.messagePatterns, vcs.Message, SourceCommitMessage)
+		for _, reference := range found {
+			log.Debug("Found issues in last commit message: ", reference.Key)
 		}
+		foundReferences = append(foundReferences, found...)
 	}
 
-	if len(foundIssueKeys) > 0 {
-		// Return found issues.
-		return issuesConfig.tracker.ProcessorFunc(foundIssueKeys)
-	} else {
-		return []buildinfo.AffectedIssue{}, nil
+	var references []IssueReference
+	discardedKeys := map[string]bool{}
+	for _, reference := range foundReferences {
+		if reference.Key = issuesConfig.tracker.NormalizeKey(vcs, reference.Key); reference.Key == "" {
+			continue
+		}
+		if reason := issuesConfig.getDiscardReason(reference.Key, reference.Project); reason != "" {
+			if !discardedKeys[reference.Key] {
+				log.Info("Discarding issue " + reference.Key + " found in " + reference.Source + ": " + reason)
+				discardedKeys[reference.Key] = true
+			}
+			continue
+		}
+		references = append(references, reference)
 	}
+	return references, commits, nil
 }
 
-// Creates a regexp handler to parse and fetch issues from the output of the git log command.
-func createLogRegExpHandler(issuesConfig *IssuesConfiguration, foundIssues *[]string) (*gofrogcmd.CmdOutputPattern, error) {
-	// Create regex pattern.
-	issueRegexp, err := clientutils.GetRegExp(issuesConfig.regexp)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create handler with exec function.
-	logRegExp := gofrogcmd.CmdOutputPattern{
-		RegExp: issueRegexp,
-		ExecFunc: func(pattern *gofrogcmd.CmdOutputPattern) (string, error) {
-			// Reached here - means no error occurred.
-			var found []string
-			for _, matches := range pattern.MatchedResults {
-				if len(matches)-1 < issuesConfig.keyGroupIndex {
-					return "", errors.New("unexpected result while parsing issues from git log. " +
-						"Make sure that the regular expression used to find issues, includes a capturing group, for the issue ID")
-				}
-				found = append(found, matches[issuesConfig.keyGroupIndex])
-			}
-			log.Debug("Found issues in commit log: ", found)
-			*foundIssues = append(*foundIssues, found...)
-			return "", nil
-		},
+// Returns the revision of the vcs url in the build-info, or an empty string if the build-info doesn't include the vcs url.
+func getPreviousVcsRevision(buildInfo *buildinfo.BuildInfo, vcsUrl string) string {
+	if vcs := findVcs(buildInfo, vcsUrl); vcs != nil {
+		log.Debug("Found previous VCS Revision " + vcs.Revision + " for VCS " + vcsUrl)
+		return vcs.Revision
 	}
-	return &logRegExp, nil
+	log.Debug("No previous VCS Revision found for VCS " + vcsUrl)
+	return ""
 }
 
-// RevisionRangeError to be thrown when revision could not be found in the git revision range.
-type RevisionRangeError struct {
-	ErrorMsg string
+// Returns the vcs entry of the vcs url in the build-info, the url may be either the ssh or https url of the repository.
+func findVcs(buildInfo *buildinfo.BuildInfo, vcsUrl string) *buildinfo.Vcs {
+	sshVcsUrl := util.GetSshVcsUrl(vcsUrl)
+	httpsVcsUrl := util.GetHttpsVcsUrl(vcsUrl)
+	for i, vcs := range buildInfo.VcsList {
+		if vcs.Url == sshVcsUrl || vcs.Url == httpsVcsUrl {
+			return &buildInfo.VcsList[i]
+		}
+	}
+	return nil
 }
 
-func (err RevisionRangeError) Error() string {
-	return err.ErrorMsg
-}
+// Returns the revision of each vcs in the baseline build, an empty revision is returned if there is no baseline build.
+func (cmd *CollectIssueCommand) getPreviousVcsRevisions(vcsList []buildinfo.Vcs) ([]string, error) {
+	issuesConfig := cmd.issuesConfiguration
+	revisions := make([]string, len(vcsList))
 
-// Creates a regexp handler to handle the event of revision missing in the git revision range.
-func createErrRegExpHandler(lastVcsRevision string) (*gofrogcmd.CmdOutputPattern, error) {
-	// Create regex pattern.
-	invalidRangeExp, err := clientutils.GetRegExp(`fatal: Invalid revision range [a-fA-F0-9]+\.\.`)
+	buildName, err := cmd.buildConfiguration.GetBuildName()
 	if err != nil {
 		return nil, err
 	}
 
-	// Create handler with exec function.
-	errRegExp := gofrogcmd.CmdOutputPattern{
-		RegExp: invalidRangeExp,
-		ExecFunc: func(pattern *gofrogcmd.CmdOutputPattern) (string, error) {
-			// Revision could not be found in the revision range, probably due to a squash / revert. Ignore and don't collect new issues.
-			errMsg := "Revision: '" + lastVcsRevision + "' that was fetched from latest build info does not exist in the git revision range. No new issues are added."
-			return "", RevisionRangeError{ErrorMsg: errMsg}
-		},
-	}
-	return &errRegExp, nil
-}
-
-func (cmd *CollectIssueCommand) getLatestVcsRevision(vcsUrl string) (string, error) {
-	// Get latest build's build-info from Artifactory
-	buildInfo, err := cmd.getLatestBuildInfo(cmd.issuesConfiguration)
-	if err != nil {
-		return "", err
-	}
+	var baselineBuildInfo *buildinfo.BuildInfo
+	switch {
+	case issuesConfig.sinceBuild != "":
+		buildInfoService, err := services.CreateExtBuildInfoService(issuesConfig.serverDetails)
+		if err != nil {
+			return nil, err
+		}
+		buildInfo, err := buildInfoService.GetBuildInfo(buildName, issuesConfig.sinceBuild, cmd.buildConfiguration.GetProject())
+		if err != nil {
+			return nil, err
+		}
+		if buildInfo == nil {
+			return nil, errorutils.CheckErrorf("Build %s/%s was not found", buildName, issuesConfig.sinceBuild)
+		}
+		baselineBuildInfo = &buildInfo.BuildInfo
 
-	sshVcsUrl := util.GetSshVcsUrl(vcsUrl)
-	httpsVcsUrl := util.GetHttpsVcsUrl(vcsUrl)
+	case issuesConfig.baseline == BaselineStatus:
+		err = cmd.walkPreviousBuildInfos(func(buildInfo *services.ExtBuildInfo) (bool, error) {
+			if buildInfo.HasStatus(issuesConfig.baselineStatus) {
+				baselineBuildInfo = &buildInfo.BuildInfo
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	// Get previous VCS Revision from BuildInfo.
-	lastVcsRevision := ""
-	for _, vcs := range buildInfo.VcsList {
-		if vcs.Url == sshVcsUrl || vcs.Url == httpsVcsUrl {
-			lastVcsRevision = vcs.Revision
-			break
+	case issuesConfig.baseline == BaselineBranch:
+		// Each repository may be on another branch, so look for the latest build of the branch per repository. Repositories
+		// without a branch have no baseline build.
+		pending := 0
+		for _, vcs := range vcsList {
+			if vcs.Branch != "" {
+				pending++
+			}
+		}
+		if pending == 0 {
+			return revisions, nil
+		}
+		found := make([]bool, len(vcsList))
+		err = cmd.walkPreviousBuildInfos(func(buildInfo *services.ExtBuildInfo) (bool, error) {
+			for i, vcs := range vcsList {
+				if found[i] || vcs.Branch == "" {
+					continue
+				}
+				if previousVcs := findVcs(&buildInfo.BuildInfo, vcs.Url); previousVcs != nil && previousVcs.Branch == vcs.Branch {
+					log.Debug("Found build " + buildInfo.Number + " of branch " + vcs.Branch + " for VCS " + vcs.Url)
+					revisions[i] = previousVcs.Revision
+					found[i] = true
+					pending--
+				}
+			}
+			return pending > 0, nil
+		})
+		return revisions, err
+
+	default:
+		buildInfoParams := artservices.BuildInfoParams{BuildName: buildName, BuildNumber: artclientutils.LatestBuildNumberKey,
+			ProjectKey: cmd.buildConfiguration.GetProject()}
+		baselineBuildInfo, err = cmd.getBuildInfo(buildInfoParams)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	log.Debug("Found previous VCS Revision: ", lastVcsRevision)
-
-	return lastVcsRevision, nil
+	if baselineBuildInfo == nil {
+		log.Debug("No baseline build was found")
+		return revisions, nil
+	}
+	log.Debug("Using build " + baselineBuildInfo.Number + " as baseline")
+	for i, vcs := range vcsList {
+		revisions[i] = getPreviousVcsRevision(baselineBuildInfo, vcs.Url)
+	}
+	return revisions, nil
 }
 
-// Returns build info, or empty build info struct if not found.
-func (cmd *CollectIssueCommand) getLatestBuildInfo(issuesConfig *IssuesConfiguration) (*buildinfo.BuildInfo, error) {
+// Returns build info, or nil if not found.
+func (cmd *CollectIssueCommand) getBuildInfo(buildInfoParams artservices.BuildInfoParams) (*buildinfo.BuildInfo, error) {
 	// Create services manager to get build-info from Artifactory.
-	sm, err := utils.CreateServiceManager(issuesConfig.serverDetails, -1, 0, false)
+	sm, err := utils.CreateServiceManager(cmd.issuesConfiguration.serverDetails, -1, 0, false)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get latest build-info from Artifactory.
-	buildName, err := cmd.buildConfiguration.GetBuildName()
-	if err != nil {
-		return nil, err
-	}
-	buildInfoParams := artservices.BuildInfoParams{BuildName: buildName, BuildNumber: artclientutils.LatestBuildNumberKey}
 	publishedBuildInfo, found, err := sm.GetBuildInfo(buildInfoParams)
-	if err != nil {
+	if err != nil || !found {
 		return nil, err
 	}
-	if !found {
-		return &buildinfo.BuildInfo{}, nil
-	}
-
 	return &publishedBuildInfo.BuildInfo, nil
 }
 
@@ -359,11 +714,26 @@ type IssuesConfiguration struct {
 	serverID          string
 	serverDetails     *utilsconfig.ServerDetails
 	logLimit          int
-	tracker           *Tracker
+	maxPreviousBuilds int
+	trackerDetails    *tracker.Details
+	tracker           tracker.Tracker
 	regexp            string
+	branchRegexp      string
+	messageRegexp     string
 	keyGroupIndex     int
+	branchPatterns    []IssuePattern
+	messagePatterns   []IssuePattern
 	aggregate         bool
 	aggregationStatus string
+	baseline          string
+	baselineStatus    string
+	sinceBuild        string
+	pathFilter        util.PathFilter
+	allowProjects     []string
+	denyProjects      []string
+	discover          bool
+	discovered        []string
+	fields            []string
 }
 
 func (ic *IssuesConfiguration) SetServerID(serverID string) *IssuesConfiguration {
@@ -376,26 +746,46 @@ func (ic *IssuesConfiguration) SetLogLimit(logLimit int) *IssuesConfiguration {
 	return ic
 }
 
-func (ic *IssuesConfiguration) SetTracker(tracker string) *IssuesConfiguration {
-	if tracker != "" {
-		ic.tracker = new(Tracker)
-		ic.tracker.Name = tracker
+// SetMaxPreviousBuilds sets the maximum number of previous builds to read when aggregating issues, or when looking for the
+// baseline build or a revision of an earlier build.
+func (ic *IssuesConfiguration) SetMaxPreviousBuilds(maxPreviousBuilds int) *IssuesConfiguration {
+	ic.maxPreviousBuilds = maxPreviousBuilds
+	return ic
+}
+
+func (ic *IssuesConfiguration) SetTracker(name string) *IssuesConfiguration {
+	if name != "" {
+		ic.trackerDetails = &tracker.Details{Name: name}
 	}
 	return ic
 }
 
 func (ic *IssuesConfiguration) SetTrackerDetails(url, username, token string) *IssuesConfiguration {
-	ic.tracker.Url = url
-	ic.tracker.Username = username
-	ic.tracker.Token = token
+	ic.trackerDetails.Url = url
+	ic.trackerDetails.Username = username
+	ic.trackerDetails.Token = token
 	return ic
 }
 
+// SetRegexp sets the newline separated regular expressions used to find issue keys in all sources.
 func (ic *IssuesConfiguration) SetRegexp(regexp string) *IssuesConfiguration {
 	ic.regexp = regexp
 	return ic
 }
 
+// SetBranchRegexp sets the newline separated regular expressions used to find issue keys in branch names, instead of the regexp.
+func (ic *IssuesConfiguration) SetBranchRegexp(branchRegexp string) *IssuesConfiguration {
+	ic.branchRegexp = branchRegexp
+	return ic
+}
+
+// SetMessageRegexp sets the newline separated regular expressions used to find issue keys in commit messages and trailers,
+// instead of the regexp.
+func (ic *IssuesConfiguration) SetMessageRegexp(messageRegexp string) *IssuesConfiguration {
+	ic.messageRegexp = messageRegexp
+	return ic
+}
+
 func (ic *IssuesConfiguration) SetKeyGroupIndex(keyGroupIndex int) *IssuesConfiguration {
 	ic.keyGroupIndex = keyGroupIndex
 	return ic
@@ -411,80 +801,155 @@ func (ic *IssuesConfiguration) SetAggregationStatus(aggregationStatus string) *I
 	return ic
 }
 
-func (ic *IssuesConfiguration) ValidateIssueConfiguration() (err error) {
-	if ic.logLimit >= 0 {
-		ic.logLimit = GitLogLimit
-	}
+func (ic *IssuesConfiguration) SetBaseline(baseline string) *IssuesConfiguration {
+	ic.baseline = strings.ToUpper(baseline)
+	return ic
+}
 
-	if ic.tracker != nil {
-		if strings.EqualFold(ic.tracker.Name, "JIRA") {
-			ic.tracker.ProcessorFunc = func(foundIssueKeys []string) ([]buildinfo.AffectedIssue, error) {
-				client, err := services.NewJiraService(ic.tracker.Url, ic.tracker.Username, ic.tracker.Token)
-				if err != nil {
-					return nil, err
-				}
-				return client.GetIssues(foundIssueKeys)
-			}
-			if ic.tracker.Url == "" {
-				log.Debug("Loading Jira details from integration ", ic.tracker.Name)
-				ic.tracker.Url = os.Getenv("int_" + ic.tracker.Name + "_url")
-				ic.tracker.Username = os.Getenv("int_" + ic.tracker.Name + "_username")
-				ic.tracker.Token = os.Getenv("int_" + ic.tracker.Name + "_token")
-			}
+func (ic *IssuesConfiguration) SetBaselineStatus(baselineStatus string) *IssuesConfiguration {
+	ic.baselineStatus = baselineStatus
+	return ic
+}
 
-			if ic.tracker.Url == "" || ic.tracker.Username == "" || ic.tracker.Token == "" {
-				return errorutils.CheckErrorf("Missing Jira details")
-			}
+func (ic *IssuesConfiguration) SetSinceBuild(sinceBuild string) *IssuesConfiguration {
+	ic.sinceBuild = sinceBuild
+	return ic
+}
 
-			ic.tracker.Name = "JIRA"
-			ic.regexp = IssueKeyRegex
-			ic.keyGroupIndex = 1
-		} else {
-			return errorutils.CheckErrorf("Unsupported tracker: %s", "tracker", err.Error())
-		}
-	}
+func (ic *IssuesConfiguration) SetIncludePaths(includePaths []string) *IssuesConfiguration {
+	ic.pathFilter.Include = includePaths
+	return ic
+}
 
-	// If no server-id provided, use default server.
-	serverDetails, err := utilsconfig.GetSpecificConfig(ic.serverID, true, false)
-	if err != nil {
-		return err
-	}
-	ic.serverDetails = serverDetails
-	return nil
+func (ic *IssuesConfiguration) SetExcludePaths(excludePaths []string) *IssuesConfiguration {
+	ic.pathFilter.Exclude = excludePaths
+	return ic
 }
 
-type Tracker struct {
-	Name          string
-	Url           string
-	Username      string
-	Token         string
-	ProcessorFunc func(foundIssueKeys []string) ([]buildinfo.AffectedIssue, error)
+func (ic *IssuesConfiguration) SetFields(fields []string) *IssuesConfiguration {
+	ic.fields = fields
+	return ic
 }
 
-type LogCmd struct {
-	logLimit        int
-	lastVcsRevision string
+func (ic *IssuesConfiguration) SetAllowProjects(allowProjects []string) *IssuesConfiguration {
+	ic.allowProjects = allowProjects
+	return ic
+}
+
+func (ic *IssuesConfiguration) SetDenyProjects(denyProjects []string) *IssuesConfiguration {
+	ic.denyProjects = denyProjects
+	return ic
 }
 
-func (logCmd *LogCmd) GetCmd() *exec.Cmd {
-	var cmd []string
-	cmd = append(cmd, "git")
-	cmd = append(cmd, "log", "--pretty='format:%d%s'", "-"+strconv.Itoa(logCmd.logLimit))
-	if logCmd.lastVcsRevision != "" {
-		cmd = append(cmd, logCmd.lastVcsRevision+"..")
+func (ic *IssuesConfiguration) SetDiscoverProjects(discover bool) *IssuesConfiguration {
+	ic.discover = discover
+	return ic
+}
+
+// Discovers the projects of the tracker, if required, so issue keys of unknown projects can be discarded.
+func (ic *IssuesConfiguration) discoverProjects() (err error) {
+	if !ic.discover || ic.discovered != nil {
+		return nil
+	}
+	discoverer, ok := ic.tracker.(tracker.ProjectDiscoverer)
+	if !ok {
+		return errorutils.CheckErrorf("Tracker %s doesn't support discovering projects", ic.tracker.Name())
 	}
-	log.Debug("Fetching git log: ", cmd)
-	return exec.Command(cmd[0], cmd[1:]...)
+	ic.discovered, err = discoverer.DiscoverProjects()
+	if err == nil && ic.discovered == nil {
+		ic.discovered = []string{}
+	}
+	return err
 }
 
-func (logCmd *LogCmd) GetEnv() map[string]string {
-	return map[string]string{}
+// Returns the reason the issue key is discarded, or an empty string if the issue key is accepted. The project is derived from the
+// issue key if it wasn't captured, issue keys of unknown projects are accepted.
+func (ic *IssuesConfiguration) getDiscardReason(key, project string) string {
+	if resolver, ok := ic.tracker.(tracker.ProjectResolver); ok && project == "" {
+		project = resolver.ProjectKey(key)
+	}
+	if project == "" {
+		return ""
+	}
+	if containsProject(ic.denyProjects, project) {
+		return "project " + project + " is denied"
+	}
+	if len(ic.allowProjects) > 0 && !containsProject(ic.allowProjects, project) {
+		return "project " + project + " is not allowed"
+	}
+	if ic.discovered != nil && !containsProject(ic.discovered, project) {
+		return "project " + project + " is not known by " + ic.tracker.Name()
+	}
+	return ""
 }
 
-func (logCmd *LogCmd) GetStdWriter() io.WriteCloser {
-	return nil
+func getFirstRegexp(regexps ...string) string {
+	for _, regexp := range regexps {
+		if regexp != "" {
+			return regexp
+		}
+	}
+	return ""
+}
+
+func containsProject(projects []string, project string) bool {
+	for _, candidate := range projects {
+		if strings.EqualFold(candidate, project) {
+			return true
+		}
+	}
+	return false
 }
 
-func (logCmd *LogCmd) GetErrWriter() io.WriteCloser {
+func (ic *IssuesConfiguration) ValidateIssueConfiguration() (err error) {
+	if ic.logLimit >= 0 {
+		ic.logLimit = GitLogLimit
+	}
+	if ic.maxPreviousBuilds <= 0 {
+		ic.maxPreviousBuilds = MaxPreviousBuilds
+	}
+
+	if ic.trackerDetails != nil {
+		ic.tracker, err = tracker.New(*ic.trackerDetails)
+		if err != nil {
+			return err
+		}
+		if err = ic.tracker.Validate(); err != nil {
+			return err
+		}
+		// The regexp of the user takes precedence over the default regexp of the tracker.
+		if ic.regexp == "" {
+			ic.regexp, ic.keyGroupIndex = ic.tracker.DefaultRegexp()
+		}
+		if ic.branchPatterns, err = newIssuePatterns(getFirstRegexp(ic.branchRegexp, ic.regexp), ic.keyGroupIndex); err != nil {
+			return err
+		}
+		if ic.messagePatterns, err = newIssuePatterns(getFirstRegexp(ic.messageRegexp, ic.regexp), ic.keyGroupIndex); err != nil {
+			return err
+		}
+		if _, ok := ic.tracker.(tracker.FieldResolver); len(ic.fields) > 0 && !ok {
+			return errorutils.CheckErrorf("Tracker %s doesn't support resolving issue fields", ic.tracker.Name())
+		}
+	}
+
+	switch ic.baseline {
+	case "":
+		ic.baseline = BaselineLatest
+	case BaselineLatest, BaselineBranch:
+	case BaselineStatus:
+		if ic.baselineStatus == "" {
+			return errorutils.CheckErrorf("The baseline-status is required when using the %s baseline", BaselineStatus)
+		}
+	default:
+		return errorutils.CheckErrorf("Unsupported baseline: %s, supported baselines are: %s, %s and %s", ic.baseline,
+			BaselineLatest, BaselineBranch, BaselineStatus)
+	}
+
+	// If no server-id provided, use default server.
+	serverDetails, err := utilsconfig.GetSpecificConfig(ic.serverID, true, false)
+	if err != nil {
+		return err
+	}
+	ic.serverDetails = serverDetails
 	return nil
 }
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"github.com/marvelution/ext-build-info/services/tracker"
+	jiratracker "github.com/marvelution/ext-build-info/services/tracker/jira"
+	"testing"
+)
+
+func TestGetDiscardReason(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowProjects []string
+		denyProjects  []string
+		discovered    []string
+		key           string
+		project       string
+		reason        string
+	}{
+		{"no projects", nil, nil, nil, "ABC-1", "", ""},
+		{"allowed", []string{"abc", "DEF"}, nil, nil, "ABC-1", "", ""},
+		{"not allowed", []string{"DEF"}, nil, nil, "ABC-1", "", "project ABC is not allowed"},
+		{"denied", nil, []string{"abc"}, nil, "ABC-1", "", "project ABC is denied"},
+		{"not denied", nil, []string{"DEF"}, nil, "ABC-1", "", ""},
+		{"deny takes precedence", []string{"ABC"}, []string{"ABC"}, nil, "ABC-1", "", "project ABC is denied"},
+		{"discovered", nil, nil, []string{"ABC"}, "ABC-1", "", ""},
+		{"not discovered", nil, nil, []string{"DEF"}, "ABC-1", "", "project ABC is not known by JIRA"},
+		{"nothing discovered", nil, nil, []string{}, "ABC-1", "", "project ABC is not known by JIRA"},
+		{"allowed but not discovered", []string{"ABC"}, nil, []string{"DEF"}, "ABC-1", "", "project ABC is not known by JIRA"},
+		{"project of the reference", []string{"ABC"}, nil, nil, "ABC-1", "DEF", "project DEF is not allowed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &IssuesConfiguration{tracker: jiratracker.NewTracker(tracker.Details{Name: jiratracker.Name}),
+				allowProjects: test.allowProjects, denyProjects: test.denyProjects, discovered: test.discovered}
+			if reason := config.getDiscardReason(test.key, test.project); reason != test.reason {
+				t.Errorf("getDiscardReason(%s, %s) = %q, want %q", test.key, test.project, reason, test.reason)
+			}
+		})
+	}
+}
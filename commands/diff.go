@@ -0,0 +1,403 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	utilsconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	DiffText = "text"
+	DiffJson = "json"
+
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// BuildInfoDiff is the difference between two build-infos, from the build that is compared to, to the build.
+type BuildInfoDiff struct {
+	Name         string         `json:"name"`
+	FromBuild    string         `json:"fromBuild"`
+	ToBuild      string         `json:"toBuild"`
+	Issues       []IssueDiff    `json:"issues"`
+	Vcs          []VcsDiff      `json:"vcs"`
+	Dependencies []ChecksumDiff `json:"dependencies"`
+	Artifacts    []ChecksumDiff `json:"artifacts"`
+	Properties   []PropertyDiff `json:"properties"`
+}
+
+type IssueDiff struct {
+	Change  string `json:"change"`
+	Key     string `json:"key"`
+	Summary string `json:"summary,omitempty"`
+	Url     string `json:"url,omitempty"`
+}
+
+type VcsDiff struct {
+	Change       string `json:"change"`
+	Url          string `json:"url"`
+	Branch       string `json:"branch,omitempty"`
+	FromRevision string `json:"fromRevision,omitempty"`
+	ToRevision   string `json:"toRevision,omitempty"`
+}
+
+// Range returns the commit range of the change, in the form git log understands.
+func (vd VcsDiff) Range() string {
+	if vd.FromRevision == "" || vd.ToRevision == "" {
+		return vd.FromRevision + vd.ToRevision
+	}
+	return vd.FromRevision + ".." + vd.ToRevision
+}
+
+type ChecksumDiff struct {
+	Change       string `json:"change"`
+	Module       string `json:"module"`
+	Id           string `json:"id"`
+	FromChecksum string `json:"fromChecksum,omitempty"`
+	ToChecksum   string `json:"toChecksum,omitempty"`
+}
+
+type PropertyDiff struct {
+	Change string `json:"change"`
+	Key    string `json:"key"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+type DiffCommand struct {
+	buildConfiguration *utils.BuildConfiguration
+	diffConfiguration  *DiffConfiguration
+}
+
+func NewDiffCommand() *DiffCommand {
+	return &DiffCommand{}
+}
+
+func (cmd *DiffCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *DiffCommand {
+	cmd.buildConfiguration = buildConfiguration
+	return cmd
+}
+
+func (cmd *DiffCommand) SetDiffConfiguration(diffConfiguration *DiffConfiguration) *DiffCommand {
+	cmd.diffConfiguration = diffConfiguration
+	return cmd
+}
+
+func (cmd *DiffCommand) Run() error {
+	config := cmd.diffConfiguration
+	buildName, err := cmd.buildConfiguration.GetBuildName()
+	if err != nil {
+		return err
+	}
+	buildInfo, err := getBuildInfo(cmd.buildConfiguration, config.serverDetails)
+	if err != nil {
+		return err
+	}
+	if buildInfo == nil {
+		return errorutils.CheckErrorf("Build-info was not found")
+	}
+
+	var otherBuildInfo *buildinfo.BuildInfo
+	if config.compareTo != "" {
+		otherBuildConfiguration := new(utils.BuildConfiguration)
+		otherBuildConfiguration.SetBuildName(buildName).SetBuildNumber(config.compareTo).SetProject(cmd.buildConfiguration.GetProject())
+		if otherBuildInfo, err = getBuildInfo(otherBuildConfiguration, config.serverDetails); err != nil {
+			return err
+		}
+	} else {
+		buildInfoService, err := services.CreateExtBuildInfoService(config.serverDetails)
+		if err != nil {
+			return err
+		}
+		// The newest build before the build is the build to compare to.
+		err = buildInfoService.WalkBuildInfos(cmd.buildConfiguration, func(previousBuildInfo *services.ExtBuildInfo) (bool, error) {
+			otherBuildInfo = &previousBuildInfo.BuildInfo
+			return false, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if otherBuildInfo == nil {
+		return errorutils.CheckErrorf("Build-info to compare to was not found")
+	}
+
+	log.Info("Comparing build " + buildName + " #" + otherBuildInfo.Number + " to #" + buildInfo.Number)
+	diff := GetBuildInfoDiff(otherBuildInfo, buildInfo)
+
+	var writer io.Writer = os.Stdout
+	if config.output != "" && config.output != "-" {
+		file, err := os.Create(config.output)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	if config.format == DiffJson {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return errorutils.CheckError(encoder.Encode(diff))
+	}
+	return errorutils.CheckError(diff.WriteText(writer))
+}
+
+// GetBuildInfoDiff returns the difference between the build-infos, all differences are sorted to keep the output stable.
+func GetBuildInfoDiff(from, to *buildinfo.BuildInfo) *BuildInfoDiff {
+	diff := &BuildInfoDiff{
+		Name:         to.Name,
+		FromBuild:    from.Number,
+		ToBuild:      to.Number,
+		Issues:       getIssueDiffs(from, to),
+		Vcs:          getVcsDiffs(from.VcsList, to.VcsList),
+		Dependencies: getChecksumDiffs(getDependencyChecksums(from), getDependencyChecksums(to)),
+		Artifacts:    getChecksumDiffs(getArtifactChecksums(from), getArtifactChecksums(to)),
+		Properties:   getPropertyDiffs(from.Properties, to.Properties),
+	}
+	return diff
+}
+
+// WriteText writes the difference as text, using + for added, - for removed and ~ for changed entries.
+func (diff *BuildInfoDiff) WriteText(writer io.Writer) error {
+	var builder strings.Builder
+	builder.WriteString("Build " + diff.Name + " #" + diff.FromBuild + " -> #" + diff.ToBuild + "\n")
+	builder.WriteString("\nIssues:\n")
+	for _, issue := range diff.Issues {
+		builder.WriteString(fmt.Sprintf("  %s %s %s\n", getChangeMarker(issue.Change), issue.Key, issue.Summary))
+	}
+	builder.WriteString("\nVCS:\n")
+	for _, vcs := range diff.Vcs {
+		builder.WriteString(fmt.Sprintf("  %s %s %s", getChangeMarker(vcs.Change), vcs.Url, vcs.Range()))
+		if vcs.Branch != "" {
+			builder.WriteString(" (" + vcs.Branch + ")")
+		}
+		builder.WriteString("\n")
+	}
+	for _, section := range []struct {
+		name  string
+		diffs []ChecksumDiff
+	}{{"Dependencies", diff.Dependencies}, {"Artifacts", diff.Artifacts}} {
+		builder.WriteString("\n" + section.name + ":\n")
+		for _, checksum := range section.diffs {
+			builder.WriteString(fmt.Sprintf("  %s %s %s", getChangeMarker(checksum.Change), checksum.Module, checksum.Id))
+			if checksum.Change == DiffChanged {
+				builder.WriteString(" " + checksum.FromChecksum + " -> " + checksum.ToChecksum)
+			}
+			builder.WriteString("\n")
+		}
+	}
+	builder.WriteString("\nProperties:\n")
+	for _, property := range diff.Properties {
+		switch property.Change {
+		case DiffAdded:
+			builder.WriteString("  + " + property.Key + "=" + property.To + "\n")
+		case DiffRemoved:
+			builder.WriteString("  - " + property.Key + "=" + property.From + "\n")
+		default:
+			builder.WriteString("  ~ " + property.Key + ": " + property.From + " -> " + property.To + "\n")
+		}
+	}
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+func getChangeMarker(change string) string {
+	switch change {
+	case DiffAdded:
+		return "+"
+	case DiffRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func getIssueDiffs(from, to *buildinfo.BuildInfo) []IssueDiff {
+	fromIssues := getAffectedIssues(from)
+	toIssues := getAffectedIssues(to)
+	diffs := []IssueDiff{}
+	for key, issue := range toIssues {
+		if _, found := fromIssues[key]; !found {
+			diffs = append(diffs, IssueDiff{Change: DiffAdded, Key: key, Summary: issue.Summary, Url: issue.Url})
+		}
+	}
+	for key, issue := range fromIssues {
+		if _, found := toIssues[key]; !found {
+			diffs = append(diffs, IssueDiff{Change: DiffRemoved, Key: key, Summary: issue.Summary, Url: issue.Url})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Change < diffs[j].Change || (diffs[i].Change == diffs[j].Change && diffs[i].Key < diffs[j].Key)
+	})
+	return diffs
+}
+
+func getAffectedIssues(buildInfo *buildinfo.BuildInfo) map[string]buildinfo.AffectedIssue {
+	issues := map[string]buildinfo.AffectedIssue{}
+	if buildInfo.Issues != nil {
+		for _, issue := range buildInfo.Issues.AffectedIssues {
+			issues[issue.Key] = issue
+		}
+	}
+	return issues
+}
+
+func getVcsDiffs(from, to []buildinfo.Vcs) []VcsDiff {
+	fromVcs := map[string]buildinfo.Vcs{}
+	for _, vcs := range from {
+		fromVcs[vcs.Url] = vcs
+	}
+	diffs := []VcsDiff{}
+	toUrls := map[string]bool{}
+	for _, vcs := range to {
+		toUrls[vcs.Url] = true
+		if previous, found := fromVcs[vcs.Url]; !found {
+			diffs = append(diffs, VcsDiff{Change: DiffAdded, Url: vcs.Url, Branch: vcs.Branch, ToRevision: vcs.Revision})
+		} else if previous.Revision != vcs.Revision {
+			diffs = append(diffs, VcsDiff{Change: DiffChanged, Url: vcs.Url, Branch: vcs.Branch, FromRevision: previous.Revision,
+				ToRevision: vcs.Revision})
+		}
+	}
+	for _, vcs := range from {
+		if !toUrls[vcs.Url] {
+			diffs = append(diffs, VcsDiff{Change: DiffRemoved, Url: vcs.Url, Branch: vcs.Branch, FromRevision: vcs.Revision})
+		}
+	}
+	return diffs
+}
+
+// Returns the checksums of the dependencies keyed by module and dependency id.
+func getDependencyChecksums(buildInfo *buildinfo.BuildInfo) map[[2]string]string {
+	checksums := map[[2]string]string{}
+	for _, module := range buildInfo.Modules {
+		for _, dependency := range module.Dependencies {
+			checksums[[2]string{module.Id, dependency.Id}] = getChecksum(dependency.Checksum)
+		}
+	}
+	return checksums
+}
+
+// Returns the checksums of the artifacts keyed by module and artifact path, or name if there is no path.
+func getArtifactChecksums(buildInfo *buildinfo.BuildInfo) map[[2]string]string {
+	checksums := map[[2]string]string{}
+	for _, module := range buildInfo.Modules {
+		for _, artifact := range module.Artifacts {
+			id := artifact.Path
+			if id == "" {
+				id = artifact.Name
+			}
+			checksums[[2]string{module.Id, id}] = getChecksum(artifact.Checksum)
+		}
+	}
+	return checksums
+}
+
+// Returns the strongest checksum available.
+func getChecksum(checksum buildinfo.Checksum) string {
+	if checksum.Sha256 != "" {
+		return checksum.Sha256
+	} else if checksum.Sha1 != "" {
+		return checksum.Sha1
+	}
+	return checksum.Md5
+}
+
+func getChecksumDiffs(from, to map[[2]string]string) []ChecksumDiff {
+	diffs := []ChecksumDiff{}
+	for key, checksum := range to {
+		if previous, found := from[key]; !found {
+			diffs = append(diffs, ChecksumDiff{Change: DiffAdded, Module: key[0], Id: key[1], ToChecksum: checksum})
+		} else if previous != checksum {
+			diffs = append(diffs, ChecksumDiff{Change: DiffChanged, Module: key[0], Id: key[1], FromChecksum: previous, ToChecksum: checksum})
+		}
+	}
+	for key, checksum := range from {
+		if _, found := to[key]; !found {
+			diffs = append(diffs, ChecksumDiff{Change: DiffRemoved, Module: key[0], Id: key[1], FromChecksum: checksum})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Module != diffs[j].Module {
+			return diffs[i].Module < diffs[j].Module
+		}
+		return diffs[i].Id < diffs[j].Id
+	})
+	return diffs
+}
+
+func getPropertyDiffs(from, to map[string]string) []PropertyDiff {
+	diffs := []PropertyDiff{}
+	for key, value := range to {
+		if previous, found := from[key]; !found {
+			diffs = append(diffs, PropertyDiff{Change: DiffAdded, Key: key, To: value})
+		} else if previous != value {
+			diffs = append(diffs, PropertyDiff{Change: DiffChanged, Key: key, From: previous, To: value})
+		}
+	}
+	for key, value := range from {
+		if _, found := to[key]; !found {
+			diffs = append(diffs, PropertyDiff{Change: DiffRemoved, Key: key, From: value})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+type DiffConfiguration struct {
+	serverID      string
+	serverDetails *utilsconfig.ServerDetails
+	compareTo     string
+	format        string
+	output        string
+}
+
+func (dc *DiffConfiguration) SetServerID(serverID string) *DiffConfiguration {
+	dc.serverID = serverID
+	return dc
+}
+
+// SetCompareTo sets the build number of the build to compare to, defaults to the previous build.
+func (dc *DiffConfiguration) SetCompareTo(compareTo string) *DiffConfiguration {
+	dc.compareTo = compareTo
+	return dc
+}
+
+func (dc *DiffConfiguration) SetFormat(format string) *DiffConfiguration {
+	dc.format = strings.ToLower(format)
+	return dc
+}
+
+// SetOutput sets the file to write the diff to, the diff is written to stdout if not set, or set to -.
+func (dc *DiffConfiguration) SetOutput(output string) *DiffConfiguration {
+	dc.output = output
+	return dc
+}
+
+func (dc *DiffConfiguration) ValidateDiffConfiguration() (err error) {
+	if dc.format == "" {
+		dc.format = DiffText
+	}
+	if dc.format != DiffText && dc.format != DiffJson {
+		return errorutils.CheckErrorf("Unsupported diff format %s, supported formats are %s and %s", dc.format, DiffText, DiffJson)
+	}
+
+	// If no server-id provided, use default server.
+	serverDetails, err := utilsconfig.GetSpecificConfig(dc.serverID, true, false)
+	if err != nil {
+		return err
+	}
+	dc.serverDetails = serverDetails
+	return nil
+}
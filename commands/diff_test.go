@@ -0,0 +1,114 @@
+package commands
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"reflect"
+	"testing"
+)
+
+func TestGetBuildInfoDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from *buildinfo.BuildInfo
+		to   *buildinfo.BuildInfo
+		diff *BuildInfoDiff
+	}{
+		{
+			name: "no changes",
+			from: &buildinfo.BuildInfo{Name: "build", Number: "1"},
+			to:   &buildinfo.BuildInfo{Name: "build", Number: "2"},
+			diff: &BuildInfoDiff{Name: "build", FromBuild: "1", ToBuild: "2", Issues: []IssueDiff{}, Vcs: []VcsDiff{},
+				Dependencies: []ChecksumDiff{}, Artifacts: []ChecksumDiff{}, Properties: []PropertyDiff{}},
+		},
+		{
+			name: "issues",
+			from: &buildinfo.BuildInfo{Name: "build", Number: "1", Issues: &buildinfo.Issues{AffectedIssues: []buildinfo.AffectedIssue{
+				{Key: "ABC-1", Summary: "First"}, {Key: "ABC-2", Summary: "Second"}}}},
+			to: &buildinfo.BuildInfo{Name: "build", Number: "2", Issues: &buildinfo.Issues{AffectedIssues: []buildinfo.AffectedIssue{
+				{Key: "ABC-4", Summary: "Fourth", Url: "https://jira.example.com/browse/ABC-4"}, {Key: "ABC-2", Summary: "Second"},
+				{Key: "ABC-3", Summary: "Third"}}}},
+			diff: &BuildInfoDiff{Name: "build", FromBuild: "1", ToBuild: "2", Issues: []IssueDiff{
+				{Change: DiffAdded, Key: "ABC-3", Summary: "Third"},
+				{Change: DiffAdded, Key: "ABC-4", Summary: "Fourth", Url: "https://jira.example.com/browse/ABC-4"},
+				{Change: DiffRemoved, Key: "ABC-1", Summary: "First"}},
+				Vcs: []VcsDiff{}, Dependencies: []ChecksumDiff{}, Artifacts: []ChecksumDiff{}, Properties: []PropertyDiff{}},
+		},
+		{
+			name: "vcs",
+			from: &buildinfo.BuildInfo{Name: "build", Number: "1", VcsList: []buildinfo.Vcs{
+				{Url: "https://example.com/app.git", Revision: "a1", Branch: "main"},
+				{Url: "https://example.com/lib.git", Revision: "b1", Branch: "main"},
+				{Url: "https://example.com/old.git", Revision: "c1", Branch: "main"}}},
+			to: &buildinfo.BuildInfo{Name: "build", Number: "2", VcsList: []buildinfo.Vcs{
+				{Url: "https://example.com/app.git", Revision: "a2", Branch: "main"},
+				{Url: "https://example.com/lib.git", Revision: "b1", Branch: "main"},
+				{Url: "https://example.com/new.git", Revision: "d1", Branch: "develop"}}},
+			diff: &BuildInfoDiff{Name: "build", FromBuild: "1", ToBuild: "2", Issues: []IssueDiff{}, Vcs: []VcsDiff{
+				{Change: DiffChanged, Url: "https://example.com/app.git", Branch: "main", FromRevision: "a1", ToRevision: "a2"},
+				{Change: DiffAdded, Url: "https://example.com/new.git", Branch: "develop", ToRevision: "d1"},
+				{Change: DiffRemoved, Url: "https://example.com/old.git", Branch: "main", FromRevision: "c1"}},
+				Dependencies: []ChecksumDiff{}, Artifacts: []ChecksumDiff{}, Properties: []PropertyDiff{}},
+		},
+		{
+			name: "modules",
+			from: &buildinfo.BuildInfo{Name: "build", Number: "1", Modules: []buildinfo.Module{{
+				Id: "app",
+				Dependencies: []buildinfo.Dependency{
+					{Id: "lib:1.0", Checksum: buildinfo.Checksum{Sha1: "s1"}},
+					{Id: "old:1.0", Checksum: buildinfo.Checksum{Md5: "m1"}}},
+				Artifacts: []buildinfo.Artifact{
+					{Name: "app.jar", Path: "com/example/app.jar", Checksum: buildinfo.Checksum{Sha256: "x1", Sha1: "s1"}}}}}},
+			to: &buildinfo.BuildInfo{Name: "build", Number: "2", Modules: []buildinfo.Module{{
+				Id: "app",
+				Dependencies: []buildinfo.Dependency{
+					{Id: "lib:1.0", Checksum: buildinfo.Checksum{Sha1: "s1"}},
+					{Id: "new:1.0", Checksum: buildinfo.Checksum{Sha256: "x2"}}},
+				Artifacts: []buildinfo.Artifact{
+					{Name: "app.jar", Path: "com/example/app.jar", Checksum: buildinfo.Checksum{Sha256: "x3", Sha1: "s1"}},
+					{Name: "app.pom", Checksum: buildinfo.Checksum{Sha1: "s2"}}}}}},
+			diff: &BuildInfoDiff{Name: "build", FromBuild: "1", ToBuild: "2", Issues: []IssueDiff{}, Vcs: []VcsDiff{},
+				Dependencies: []ChecksumDiff{
+					{Change: DiffAdded, Module: "app", Id: "new:1.0", ToChecksum: "x2"},
+					{Change: DiffRemoved, Module: "app", Id: "old:1.0", FromChecksum: "m1"}},
+				Artifacts: []ChecksumDiff{
+					{Change: DiffAdded, Module: "app", Id: "app.pom", ToChecksum: "s2"},
+					{Change: DiffChanged, Module: "app", Id: "com/example/app.jar", FromChecksum: "x1", ToChecksum: "x3"}},
+				Properties: []PropertyDiff{}},
+		},
+		{
+			name: "properties",
+			from: &buildinfo.BuildInfo{Name: "build", Number: "1", Properties: map[string]string{"a": "1", "b": "1", "c": "1"}},
+			to:   &buildinfo.BuildInfo{Name: "build", Number: "2", Properties: map[string]string{"b": "2", "c": "1", "d": "1"}},
+			diff: &BuildInfoDiff{Name: "build", FromBuild: "1", ToBuild: "2", Issues: []IssueDiff{}, Vcs: []VcsDiff{},
+				Dependencies: []ChecksumDiff{}, Artifacts: []ChecksumDiff{}, Properties: []PropertyDiff{
+					{Change: DiffRemoved, Key: "a", From: "1"},
+					{Change: DiffChanged, Key: "b", From: "1", To: "2"},
+					{Change: DiffAdded, Key: "d", To: "1"}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := GetBuildInfoDiff(test.from, test.to); !reflect.DeepEqual(diff, test.diff) {
+				t.Errorf("GetBuildInfoDiff() = %+v, want %+v", diff, test.diff)
+			}
+		})
+	}
+}
+
+func TestVcsDiffRange(t *testing.T) {
+	tests := []struct {
+		diff       VcsDiff
+		rangeValue string
+	}{
+		{VcsDiff{Change: DiffChanged, FromRevision: "a1", ToRevision: "a2"}, "a1..a2"},
+		{VcsDiff{Change: DiffAdded, ToRevision: "a2"}, "a2"},
+		{VcsDiff{Change: DiffRemoved, FromRevision: "a1"}, "a1"},
+	}
+	for _, test := range tests {
+		t.Run(test.diff.Change, func(t *testing.T) {
+			if rangeValue := test.diff.Range(); rangeValue != test.rangeValue {
+				t.Errorf("Range() = %s, want %s", rangeValue, test.rangeValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/common"
+	"github.com/marvelution/ext-build-info/services/jira"
+	jiratracker "github.com/marvelution/ext-build-info/services/tracker/jira"
+	"github.com/marvelution/ext-build-info/util"
+	"strconv"
+)
+
+type FixVersionCommand struct {
+	buildConfiguration *utils.BuildConfiguration
+	jiraConfiguration  *JiraConfiguration
+	deploymentInfo     *DeploymentInfo
+	version            string
+	projects           []string
+	release            bool
+}
+
+func NewFixVersionCommand() *FixVersionCommand {
+	return &FixVersionCommand{}
+}
+
+func (cmd *FixVersionCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *FixVersionCommand {
+	cmd.buildConfiguration = buildConfiguration
+	return cmd
+}
+
+func (cmd *FixVersionCommand) SetJiraConfiguration(jiraConfiguration *JiraConfiguration) *FixVersionCommand {
+	cmd.jiraConfiguration = jiraConfiguration
+	return cmd
+}
+
+// SetDeploymentInfo enables to set the fix version on the issues of a deployment, instead of the issues of a build.
+func (cmd *FixVersionCommand) SetDeploymentInfo(deploymentInfo *DeploymentInfo) *FixVersionCommand {
+	cmd.deploymentInfo = deploymentInfo
+	return cmd
+}
+
+// SetVersion sets the name of the version, defaults to the build number.
+func (cmd *FixVersionCommand) SetVersion(version string) *FixVersionCommand {
+	cmd.version = version
+	return cmd
+}
+
+// SetProjects limits the projects to manage the version in, defaults to the projects of all affected issues.
+func (cmd *FixVersionCommand) SetProjects(projects []string) *FixVersionCommand {
+	cmd.projects = projects
+	return cmd
+}
+
+// SetRelease enables to mark the version released once a deployment to production is successful.
+func (cmd *FixVersionCommand) SetRelease(release bool) *FixVersionCommand {
+	cmd.release = release
+	return cmd
+}
+
+func (cmd *FixVersionCommand) Run() error {
+	var issueKeys []string
+	var buildNumber string
+	release := false
+	if cmd.deploymentInfo != nil {
+		log.Info("Collecting deployment issues to set the fix version on.")
+		pipelinesService, err := services.NewPipelinesService(*cmd.jiraConfiguration.serverDetails)
+		if err != nil {
+			return err
+		}
+		currentRun, buildInfos, err := getDeployedBuildInfos(pipelinesService, cmd.buildConfiguration, cmd.jiraConfiguration, cmd.deploymentInfo)
+		if err != nil {
+			return err
+		}
+		for _, buildInfo := range buildInfos {
+			getIssueKeys(&buildInfo, &issueKeys)
+			buildNumber = buildInfo.Number
+		}
+		if cmd.release {
+			state, err := getDeploymentState(pipelinesService, currentRun, cmd.jiraConfiguration.includePrePostRunSteps)
+			if err != nil {
+				return err
+			}
+			release = state == common.Successful && cmd.deploymentInfo.GetEnvironment().Type == jira.Production
+			if !release {
+				log.Info("Not releasing, the deployment to " + cmd.deploymentInfo.environment + " is not a successful production deployment")
+			}
+		}
+	} else {
+		log.Info("Collecting build issues to set the fix version on.")
+		buildInfo, err := getBuildInfo(cmd.buildConfiguration, cmd.jiraConfiguration.serverDetails)
+		if err != nil {
+			return err
+		}
+		if buildInfo == nil {
+			return errorutils.CheckErrorf("Build-info was not found")
+		}
+		getIssueKeys(buildInfo, &issueKeys)
+		buildNumber = buildInfo.Number
+	}
+
+	version := cmd.version
+	if version == "" {
+		version = buildNumber
+	}
+	issueKeys = util.RemoveDuplicate(issueKeys)
+	if len(issueKeys) == 0 || version == "" {
+		log.Info("Nothing to do, no issues or version found")
+		return nil
+	}
+
+	client, err := services.NewJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId, cmd.jiraConfiguration.jiraSecret)
+	if err != nil {
+		return err
+	}
+	client.SetDryRun(cmd.jiraConfiguration.dryRun)
+
+	var failedIssueKeys, failedProjectKeys []string
+	for projectKey, projectIssueKeys := range cmd.getProjectIssueKeys(issueKeys) {
+		projectVersion, err := client.FindOrCreateVersion(projectKey, version)
+		if err != nil {
+			log.Warn("Failed to find or create version " + version + " in project " + projectKey + ": " + err.Error())
+			failedIssueKeys = append(failedIssueKeys, projectIssueKeys...)
+			continue
+		}
+		for _, issueKey := range projectIssueKeys {
+			if err := client.AddFixVersion(issueKey, projectVersion.Name); err != nil {
+				log.Warn("Failed to set fix version " + projectVersion.Name + " on issue " + issueKey + ": " + err.Error())
+				failedIssueKeys = append(failedIssueKeys, issueKey)
+			} else {
+				log.Info("Set fix version " + projectVersion.Name + " on issue " + issueKey)
+			}
+		}
+		if release {
+			if err := client.ReleaseVersion(projectVersion); err != nil {
+				log.Warn("Failed to release version " + projectVersion.Name + " in project " + projectKey + ": " + err.Error())
+				failedProjectKeys = append(failedProjectKeys, projectKey)
+			} else {
+				log.Info("Released version " + projectVersion.Name + " in project " + projectKey)
+			}
+		}
+	}
+	if len(failedProjectKeys) > 0 && cmd.jiraConfiguration.failOnReject {
+		return errorutils.CheckErrorf("There are " + strconv.Itoa(len(failedProjectKeys)) + " projects in which the version failed to release")
+	}
+	if len(failedIssueKeys) > 0 && cmd.jiraConfiguration.failOnReject {
+		return errorutils.CheckErrorf("There are " + strconv.Itoa(len(failedIssueKeys)) + " issues that failed to update")
+	}
+	return nil
+}
+
+// Returns the issue keys grouped by the key of their project, leaving out issues of projects that are not managed.
+func (cmd *FixVersionCommand) getProjectIssueKeys(issueKeys []string) map[string][]string {
+	projectIssueKeys := map[string][]string{}
+	for _, issueKey := range issueKeys {
+		projectKey := jiratracker.ProjectKey(issueKey)
+		if projectKey == "" {
+			continue
+		}
+		if len(cmd.projects) > 0 && !containsProject(cmd.projects, projectKey) {
+			log.Info("Skipping issue " + issueKey + " since project " + projectKey + " is not managed")
+			continue
+		}
+		projectIssueKeys[projectKey] = append(projectIssueKeys[projectKey], issueKey)
+	}
+	return projectIssueKeys
+}
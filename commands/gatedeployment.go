@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/common"
+	"github.com/marvelution/ext-build-info/services/jira"
+	"time"
+)
+
+const (
+	// GatingExitCodePrevented is the exit code when the deployment is prevented by Jira.
+	GatingExitCodePrevented = 3
+	// GatingExitCodeTimedOut is the exit code when Jira didn't allow or prevent the deployment before the timeout.
+	GatingExitCodeTimedOut = 4
+	// GatingExitCodeInvalid is the exit code when the deployment can't be gated by Jira, e.g. when change management is not set up
+	// for the environment.
+	GatingExitCodeInvalid = 5
+
+	GatingDefaultTimeout  = 30 * time.Minute
+	GatingDefaultInterval = 30 * time.Second
+)
+
+type GateDeploymentCommand struct {
+	buildConfiguration  *utils.BuildConfiguration
+	jiraConfiguration   *JiraConfiguration
+	deploymentInfo      *DeploymentInfo
+	gatingConfiguration *GatingConfiguration
+}
+
+func NewGateDeploymentCommand() *GateDeploymentCommand {
+	return &GateDeploymentCommand{}
+}
+
+func (cmd *GateDeploymentCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *GateDeploymentCommand {
+	cmd.buildConfiguration = buildConfiguration
+	return cmd
+}
+
+func (cmd *GateDeploymentCommand) SetJiraConfiguration(jiraConfiguration *JiraConfiguration) *GateDeploymentCommand {
+	cmd.jiraConfiguration = jiraConfiguration
+	return cmd
+}
+
+func (cmd *GateDeploymentCommand) SetDeploymentInfo(deploymentInfo *DeploymentInfo) *GateDeploymentCommand {
+	cmd.deploymentInfo = deploymentInfo
+	return cmd
+}
+
+func (cmd *GateDeploymentCommand) SetGatingConfiguration(gatingConfiguration *GatingConfiguration) *GateDeploymentCommand {
+	cmd.gatingConfiguration = gatingConfiguration
+	return cmd
+}
+
+func (cmd *GateDeploymentCommand) Run() error {
+	log.Info("Collecting deployment-info to gate in Jira.")
+
+	buildInfos, err := cmd.getBuildInfos()
+	if err != nil {
+		return err
+	}
+	var issueKeys []string
+	var buildInfo = &buildinfo.BuildInfo{}
+	for index, info := range buildInfos {
+		getIssueKeys(&info, &issueKeys)
+		if index == len(buildInfos)-1 {
+			buildInfo = &info
+		}
+	}
+	if len(issueKeys) == 0 {
+		log.Info("Nothing to gate, no issue found")
+		return nil
+	}
+
+	jiraDeploymentInfo := cmd.deploymentInfo.GetJiraDeploymentInfo(buildInfo, issueKeys, common.Pending)
+	jiraDeploymentInfo.Commands = []jira.Command{{Command: jira.InitiateDeploymentGating}}
+
+	client, err := services.NewOAuthJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId,
+		cmd.jiraConfiguration.jiraSecret, cmd.jiraConfiguration.dryRun)
+	if err != nil {
+		return err
+	}
+	response, err := client.SendDeploymentInfo(jiraDeploymentInfo)
+	if err != nil {
+		return err
+	}
+	logDeploymentInfoResponse(response)
+	if len(response.AcceptedDeployments) == 0 {
+		return errorutils.CheckErrorf("The deployment was not accepted by Jira, unable to gate the deployment")
+	}
+
+	return cmd.waitForGatingStatus(client, jiraDeploymentInfo)
+}
+
+// Returns the build-infos that are deployed, these are the builds since the previous deployment if the deployment is run by
+// JFrog Pipelines, otherwise only the build of the build configuration.
+func (cmd *GateDeploymentCommand) getBuildInfos() ([]buildinfo.BuildInfo, error) {
+	pipelinesService, err := services.NewPipelinesService(*cmd.jiraConfiguration.serverDetails)
+	if err != nil {
+		return nil, err
+	}
+	_, buildInfos, err := getDeployedBuildInfos(pipelinesService, cmd.buildConfiguration, cmd.jiraConfiguration, cmd.deploymentInfo)
+	return buildInfos, err
+}
+
+// Polls the gating status of the deployment until Jira allowed or prevented the deployment, or until the timeout. Returns an error
+// with a distinct exit code if the deployment may not continue.
+func (cmd *GateDeploymentCommand) waitForGatingStatus(client *services.JiraService, jiraDeploymentInfo jira.DeploymentInfo) error {
+	config := cmd.gatingConfiguration
+	deadline := time.Now().Add(config.timeout)
+	for {
+		status, err := client.GetDeploymentGatingStatus(jiraDeploymentInfo.Pipeline.Id, jiraDeploymentInfo.Environment.Id,
+			jiraDeploymentInfo.DeploymentSequenceNumber)
+		var rateLimitError *services.RateLimitError
+		if errors.As(err, &rateLimitError) {
+			log.Warn(rateLimitError.Error())
+			wait := time.Until(deadline)
+			if wait <= 0 {
+				return coreutils.CliError{ExitCode: coreutils.ExitCode{Code: GatingExitCodeTimedOut},
+					ErrorMsg: fmt.Sprintf("Deployment %s to %s was not allowed or prevented after %s, Jira kept rate limiting the requests",
+						jiraDeploymentInfo.DisplayName, cmd.deploymentInfo.environment, config.timeout)}
+			}
+			// Check the status one last time at the deadline, if Jira asks to wait beyond the deadline.
+			if rateLimitError.RetryAfter < wait {
+				wait = rateLimitError.RetryAfter
+			}
+			time.Sleep(wait)
+			continue
+		} else if err != nil {
+			return err
+		}
+		for _, detail := range status.Details {
+			log.Debug(fmt.Sprintf("Gating %s %s %s", detail.Type, detail.IssueKey, detail.IssueLink))
+		}
+
+		switch status.GatingStatus {
+		case jira.GatingAllowed:
+			log.Info("Deployment " + jiraDeploymentInfo.DisplayName + " to " + cmd.deploymentInfo.environment + " is allowed by Jira")
+			return nil
+		case jira.GatingPrevented:
+			return coreutils.CliError{ExitCode: coreutils.ExitCode{Code: GatingExitCodePrevented},
+				ErrorMsg: "Deployment " + jiraDeploymentInfo.DisplayName + " to " + cmd.deploymentInfo.environment + " is prevented by Jira"}
+		case jira.GatingInvalid:
+			return coreutils.CliError{ExitCode: coreutils.ExitCode{Code: GatingExitCodeInvalid},
+				ErrorMsg: "Deployment " + jiraDeploymentInfo.DisplayName + " to " + cmd.deploymentInfo.environment + " cannot be gated by Jira"}
+		}
+
+		if !time.Now().Add(config.interval).Before(deadline) {
+			return coreutils.CliError{ExitCode: coreutils.ExitCode{Code: GatingExitCodeTimedOut},
+				ErrorMsg: fmt.Sprintf("Deployment %s to %s is still %s after %s", jiraDeploymentInfo.DisplayName,
+					cmd.deploymentInfo.environment, status.GatingStatus, config.timeout)}
+		}
+		log.Info(fmt.Sprintf("Deployment %s to %s is %s, checking again in %s", jiraDeploymentInfo.DisplayName,
+			cmd.deploymentInfo.environment, status.GatingStatus, config.interval))
+		time.Sleep(config.interval)
+	}
+}
+
+type GatingConfiguration struct {
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// SetTimeout sets the maximum duration to wait for Jira to allow or prevent the deployment.
+func (gc *GatingConfiguration) SetTimeout(timeout time.Duration) *GatingConfiguration {
+	gc.timeout = timeout
+	return gc
+}
+
+// SetInterval sets the duration between two checks of the gating status.
+func (gc *GatingConfiguration) SetInterval(interval time.Duration) *GatingConfiguration {
+	gc.interval = interval
+	return gc
+}
+
+func (gc *GatingConfiguration) ValidateGatingConfiguration() error {
+	if gc.timeout == 0 {
+		gc.timeout = GatingDefaultTimeout
+	}
+	if gc.interval == 0 {
+		gc.interval = GatingDefaultInterval
+	}
+	if gc.timeout < 0 || gc.interval < 0 {
+		return errorutils.CheckErrorf("The timeout and interval cannot be negative")
+	}
+	return nil
+}
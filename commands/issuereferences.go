@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/marvelution/ext-build-info/util"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	RelationshipFixed      = "fixed"
+	RelationshipReferenced = "referenced"
+	RelationshipMentioned  = "mentioned"
+
+	SourceBranch        = "branch"
+	SourceCommitMessage = "commit-message"
+	SourceTrailer       = "trailer"
+)
+
+var (
+	// Keywords, optionally followed by a list of other issue keys, that close the issue, like Fixes ABC-1 or Closes #1, #2
+	fixedKeywordRegexp = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)(?::\s*|\s+)(?:[^\s,]+\s*(?:,|\band\b)\s*)*$`)
+	// Keywords, optionally followed by a list of other issue keys, that reference the issue, like Refs ABC-1 or See #1
+	referencedKeywordRegexp = regexp.MustCompile(`(?i)\b(?:refs?|references?|see|related(?:\s+to)?|part\s+of)(?::\s*|\s+)(?:[^\s,]+\s*(?:,|\band\b)\s*)*$`)
+
+	trailerRelationships = map[string]string{
+		"fixes":      RelationshipFixed,
+		"fixed":      RelationshipFixed,
+		"closes":     RelationshipFixed,
+		"closed":     RelationshipFixed,
+		"resolves":   RelationshipFixed,
+		"resolved":   RelationshipFixed,
+		"refs":       RelationshipReferenced,
+		"references": RelationshipReferenced,
+		"related":    RelationshipReferenced,
+		"related-to": RelationshipReferenced,
+		"see-also":   RelationshipReferenced,
+		"part-of":    RelationshipReferenced,
+	}
+
+	relationshipRanks = map[string]int{RelationshipMentioned: 1, RelationshipReferenced: 2, RelationshipFixed: 3}
+)
+
+// IssueReference is an issue key found in a source, together with the relationship of the build to the issue. The project is
+// set if captured by the issue pattern, and the commit is the hash of the commit the issue key was found in, if any.
+type IssueReference struct {
+	Key          string
+	Project      string
+	Relationship string
+	Source       string
+	Commit       string
+}
+
+// IssueRelationship is the strongest relationship of the build to an issue, and all the sources the issue was found in.
+type IssueRelationship struct {
+	Relationship string
+	Sources      []string
+}
+
+// IssuePattern is a regular expression used to find issue keys. The issue key is captured by the group named key, or otherwise by
+// the capturing group at the key group index. The optional groups named project and relation capture the project of the issue,
+// and the keyword that relates the issue to the build, like Fixes.
+type IssuePattern struct {
+	regexp             *regexp.Regexp
+	keyGroupIndex      int
+	projectGroupIndex  int
+	relationGroupIndex int
+}
+
+// Returns the issue patterns of the newline separated list of regular expressions, the key group index is used for patterns
+// without a group named key.
+func newIssuePatterns(regexps string, keyGroupIndex int) ([]IssuePattern, error) {
+	var patterns []IssuePattern
+	for _, expression := range strings.Split(regexps, "\n") {
+		if expression = strings.TrimSpace(expression); expression == "" {
+			continue
+		}
+		issueRegexp, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, errorutils.CheckErrorf("Invalid issue regular expression %s: %s", expression, err.Error())
+		}
+		pattern := IssuePattern{
+			regexp:             issueRegexp,
+			keyGroupIndex:      keyGroupIndex,
+			projectGroupIndex:  issueRegexp.SubexpIndex("project"),
+			relationGroupIndex: issueRegexp.SubexpIndex("relation"),
+		}
+		if index := issueRegexp.SubexpIndex("key"); index > 0 {
+			pattern.keyGroupIndex = index
+		} else if keyGroupIndex > issueRegexp.NumSubexp() {
+			return nil, errorutils.CheckErrorf("The issue regular expression %s has no capturing group %d, or group named key, "+
+				"for the issue ID", expression, keyGroupIndex)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// Returns the text captured by the group, or an empty string if the group didn't participate in the match.
+func getGroup(text string, matches []int, groupIndex int) (string, int) {
+	if groupIndex <= 0 || matches[groupIndex*2] < 0 {
+		return "", -1
+	}
+	return text[matches[groupIndex*2]:matches[groupIndex*2+1]], matches[groupIndex*2]
+}
+
+// Returns the issue references found in the text using all the issue patterns. The relationship is derived from the relation
+// group if captured, or from the closing and referencing keywords preceding the issue key, and defaults to mentioned.
+func findIssueReferences(patterns []IssuePattern, text, source string) []IssueReference {
+	var found []IssueReference
+	for _, pattern := range patterns {
+		for _, matches := range pattern.regexp.FindAllStringSubmatchIndex(text, -1) {
+			key, start := getGroup(text, matches, pattern.keyGroupIndex)
+			if key == "" {
+				continue
+			}
+			relationship := RelationshipMentioned
+			if relation, _ := getGroup(text, matches, pattern.relationGroupIndex); relation != "" {
+				relationship = getRelationRelationship(relation)
+			}
+			if relationship == RelationshipMentioned {
+				relationship = getKeywordRelationship(text[:start])
+			}
+			project, _ := getGroup(text, matches, pattern.projectGroupIndex)
+			found = append(found, IssueReference{Key: key, Project: project, Relationship: relationship, Source: source})
+		}
+	}
+	return found
+}
+
+// Returns the issue references found in the values of the trailers that relate to issues, like Fixes: ABC-1 or Refs: ABC-2.
+func findTrailerIssueReferences(patterns []IssuePattern, trailers []util.Trailer) []IssueReference {
+	var found []IssueReference
+	for _, trailer := range trailers {
+		relationship, ok := trailerRelationships[strings.ToLower(trailer.Token)]
+		if !ok {
+			continue
+		}
+		for _, reference := range findIssueReferences(patterns, trailer.Value, SourceTrailer) {
+			reference.Relationship = relationship
+			found = append(found, reference)
+		}
+	}
+	return found
+}
+
+// Returns the relationship of a relation keyword, like Fixes or Refs, captured by an issue pattern.
+func getRelationRelationship(relation string) string {
+	if relationship, found := trailerRelationships[strings.ToLower(relation)]; found {
+		return relationship
+	}
+	return getKeywordRelationship(relation + " ")
+}
+
+func getKeywordRelationship(precedingText string) string {
+	if fixedKeywordRegexp.MatchString(precedingText) {
+		return RelationshipFixed
+	} else if referencedKeywordRegexp.MatchString(precedingText) {
+		return RelationshipReferenced
+	}
+	return RelationshipMentioned
+}
+
+// Returns the relationship and sources of the issue as recorded in the build-info properties by collect-issues, the relationship
+// is empty if it wasn't recorded.
+func getIssueRelationship(properties map[string]string, key string) IssueRelationship {
+	return IssueRelationship{
+		Relationship: properties[IssuesPropertyPrefix+key+".relationship"],
+		Sources:      util.SplitList(properties[IssuesPropertyPrefix+key+".sources"]),
+	}
+}
+
+// Returns the relationship of each issue key, using the strongest relationship of all references to the issue.
+func getIssueRelationships(references []IssueReference) map[string]*IssueRelationship {
+	relationships := map[string]*IssueRelationship{}
+	for _, reference := range references {
+		relationship, found := relationships[reference.Key]
+		if !found {
+			relationship = &IssueRelationship{Relationship: reference.Relationship}
+			relationships[reference.Key] = relationship
+		} else if relationshipRanks[reference.Relationship] > relationshipRanks[relationship.Relationship] {
+			relationship.Relationship = reference.Relationship
+		}
+		relationship.Sources = util.RemoveDuplicate(append(relationship.Sources, reference.Source))
+	}
+	for _, relationship := range relationships {
+		sort.Strings(relationship.Sources)
+	}
+	return relationships
+}
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"github.com/marvelution/ext-build-info/util"
+	"reflect"
+	"testing"
+)
+
+const testIssueRegexp = `([A-Z][A-Z0-9]+-[0-9]+)`
+
+func TestGetKeywordRelationship(t *testing.T) {
+	tests := []struct {
+		precedingText string
+		relationship  string
+	}{
+		{"", RelationshipMentioned},
+		{"Add feature for ", RelationshipMentioned},
+		{"Fixes ", RelationshipFixed},
+		{"fix ", RelationshipFixed},
+		{"Fixed: ", RelationshipFixed},
+		{"Closes ", RelationshipFixed},
+		{"resolved ", RelationshipFixed},
+		{"Fixes ABC-1, ", RelationshipFixed},
+		{"Fixes ABC-1 and ", RelationshipFixed},
+		{"Refs ", RelationshipReferenced},
+		{"See: ", RelationshipReferenced},
+		{"Related to ", RelationshipReferenced},
+		{"Part of ", RelationshipReferenced},
+		{"Fixes the bug of ", RelationshipMentioned},
+		{"Prefixes ", RelationshipMentioned},
+		{"Fixes ABC-1 and see ", RelationshipReferenced},
+	}
+	for _, test := range tests {
+		t.Run(test.precedingText, func(t *testing.T) {
+			if relationship := getKeywordRelationship(test.precedingText); relationship != test.relationship {
+				t.Errorf("getKeywordRelationship(%q) = %s, want %s", test.precedingText, relationship, test.relationship)
+			}
+		})
+	}
+}
+
+func TestFindIssueReferencesKeywords(t *testing.T) {
+	patterns, err := newIssuePatterns(testIssueRegexp, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		text       string
+		references []IssueReference
+	}{
+		{"no issues", "Add feature", nil},
+		{"mentioned", "ABC-1 Add feature", []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipMentioned, Source: SourceCommitMessage}}},
+		{"fixed", "Add feature, fixes ABC-1", []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipFixed, Source: SourceCommitMessage}}},
+		{"fixed list", "Closes ABC-1, ABC-2 and ABC-3", []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipFixed, Source: SourceCommitMessage},
+			{Key: "ABC-2", Relationship: RelationshipFixed, Source: SourceCommitMessage},
+			{Key: "ABC-3", Relationship: RelationshipFixed, Source: SourceCommitMessage}}},
+		{"mixed", "Fixes ABC-1, refs ABC-2 for ABC-3", []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipFixed, Source: SourceCommitMessage},
+			{Key: "ABC-2", Relationship: RelationshipReferenced, Source: SourceCommitMessage},
+			{Key: "ABC-3", Relationship: RelationshipMentioned, Source: SourceCommitMessage}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if references := findIssueReferences(patterns, test.text, SourceCommitMessage); !reflect.DeepEqual(references, test.references) {
+				t.Errorf("findIssueReferences(%q) = %v, want %v", test.text, references, test.references)
+			}
+		})
+	}
+}
+
+func TestFindTrailerIssueReferences(t *testing.T) {
+	patterns, err := newIssuePatterns(testIssueRegexp, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		trailers   []util.Trailer
+		references []IssueReference
+	}{
+		{"no trailers", nil, nil},
+		{"unrelated trailer", []util.Trailer{{Token: "Signed-off-by", Value: "ABC-1 <abc@example.com>"}}, nil},
+		{"fixes", []util.Trailer{{Token: "Fixes", Value: "ABC-1"}}, []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipFixed, Source: SourceTrailer}}},
+		{"case insensitive", []util.Trailer{{Token: "CLOSES", Value: "ABC-1"}}, []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipFixed, Source: SourceTrailer}}},
+		{"references", []util.Trailer{{Token: "Related-to", Value: "ABC-1, ABC-2"}}, []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipReferenced, Source: SourceTrailer},
+			{Key: "ABC-2", Relationship: RelationshipReferenced, Source: SourceTrailer}}},
+		{"token takes precedence over keywords", []util.Trailer{{Token: "Refs", Value: "fixes ABC-1"}}, []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipReferenced, Source: SourceTrailer}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if references := findTrailerIssueReferences(patterns, test.trailers); !reflect.DeepEqual(references, test.references) {
+				t.Errorf("findTrailerIssueReferences(%v) = %v, want %v", test.trailers, references, test.references)
+			}
+		})
+	}
+}
+
+func TestGetIssueRelationships(t *testing.T) {
+	references := []IssueReference{
+		{Key: "ABC-1", Relationship: RelationshipMentioned, Source: SourceBranch},
+		{Key: "ABC-1", Relationship: RelationshipFixed, Source: SourceTrailer},
+		{Key: "ABC-1", Relationship: RelationshipReferenced, Source: SourceCommitMessage},
+		{Key: "ABC-2", Relationship: RelationshipReferenced, Source: SourceCommitMessage},
+		{Key: "ABC-2", Relationship: RelationshipMentioned, Source: SourceCommitMessage},
+	}
+	expected := map[string]*IssueRelationship{
+		"ABC-1": {Relationship: RelationshipFixed, Sources: []string{SourceBranch, SourceCommitMessage, SourceTrailer}},
+		"ABC-2": {Relationship: RelationshipReferenced, Sources: []string{SourceCommitMessage}},
+	}
+	if relationships := getIssueRelationships(references); !reflect.DeepEqual(relationships, expected) {
+		t.Errorf("getIssueRelationships() = %v, want %v", relationships, expected)
+	}
+}
+
+func TestGetIssueRelationship(t *testing.T) {
+	properties := map[string]string{
+		IssuesPropertyPrefix + "ABC-1.relationship": RelationshipFixed,
+		IssuesPropertyPrefix + "ABC-1.sources":      SourceBranch + "," + SourceTrailer,
+	}
+	tests := []struct {
+		key          string
+		relationship IssueRelationship
+	}{
+		{"ABC-1", IssueRelationship{Relationship: RelationshipFixed, Sources: []string{SourceBranch, SourceTrailer}}},
+		{"ABC-2", IssueRelationship{}},
+	}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			if relationship := getIssueRelationship(properties, test.key); !reflect.DeepEqual(relationship, test.relationship) {
+				t.Errorf("getIssueRelationship(%s) = %v, want %v", test.key, relationship, test.relationship)
+			}
+		})
+	}
+}
+
+func TestNewIssuePatterns(t *testing.T) {
+	tests := []struct {
+		name          string
+		regexps       string
+		keyGroupIndex int
+		patterns      int
+		wantErr       bool
+	}{
+		{"empty", "", 1, 0, false},
+		{"single", testIssueRegexp, 1, 1, false},
+		{"multiple with blank lines", testIssueRegexp + "\n\n  #([0-9]+)  \n", 1, 2, false},
+		{"named key group", `(?P<project>[A-Z]+)-(?P<key>[0-9]+)`, 3, 1, false},
+		{"invalid regexp", `([A-Z]+`, 1, 0, true},
+		{"missing key group", `[A-Z]+-[0-9]+`, 1, 0, true},
+		{"key group index out of range", testIssueRegexp, 2, 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			patterns, err := newIssuePatterns(test.regexps, test.keyGroupIndex)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("newIssuePatterns() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if len(patterns) != test.patterns {
+				t.Errorf("newIssuePatterns() returned %d patterns, want %d", len(patterns), test.patterns)
+			}
+		})
+	}
+}
+
+func TestFindIssueReferencesNamedGroups(t *testing.T) {
+	tests := []struct {
+		name       string
+		regexps    string
+		text       string
+		references []IssueReference
+	}{
+		{"key group index", `(?:^|/)(([A-Z]+)-[0-9]+)`, "feature/ABC-1-add-feature", []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipMentioned, Source: SourceBranch}}},
+		{"named key and project groups", `(?P<key>(?P<project>[a-z-]+/[a-z-]+)#[0-9]+)`, "See owner/repo#12", []IssueReference{
+			{Key: "owner/repo#12", Project: "owner/repo", Relationship: RelationshipReferenced, Source: SourceBranch}}},
+		{"named relation group", `(?:(?P<relation>[A-Za-z-]+):\s*)?(?P<key>[A-Z]+-[0-9]+)`, "Resolves: ABC-1, Refs: ABC-2, ABC-3",
+			[]IssueReference{
+				{Key: "ABC-1", Relationship: RelationshipFixed, Source: SourceBranch},
+				{Key: "ABC-2", Relationship: RelationshipReferenced, Source: SourceBranch},
+				{Key: "ABC-3", Relationship: RelationshipReferenced, Source: SourceBranch}}},
+		{"unknown relation falls back to keywords", `(?:(?P<relation>[A-Za-z]+)\s+)?(?P<key>[A-Z]+-[0-9]+)`, "Implements ABC-1",
+			[]IssueReference{{Key: "ABC-1", Relationship: RelationshipMentioned, Source: SourceBranch}}},
+		{"multiple patterns", testIssueRegexp + "\n" + `(?P<key>#[0-9]+)`, "ABC-1 fixes #2", []IssueReference{
+			{Key: "ABC-1", Relationship: RelationshipMentioned, Source: SourceBranch},
+			{Key: "#2", Relationship: RelationshipFixed, Source: SourceBranch}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			patterns, err := newIssuePatterns(test.regexps, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if references := findIssueReferences(patterns, test.text, SourceBranch); !reflect.DeepEqual(references, test.references) {
+				t.Errorf("findIssueReferences(%q) = %v, want %v", test.text, references, test.references)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ type JiraConfiguration struct {
 	dryRun                 bool
 	includePrePostRunSteps bool
 	failOnReject           bool
+	remoteLinks            bool
+	tokenAuth              bool
 }
 
 func (jc *JiraConfiguration) SetServerID(serverID string) *JiraConfiguration {
@@ -51,6 +53,21 @@ func (jc *JiraConfiguration) SetFailOnReject(failOnReject bool) *JiraConfigurati
 	return jc
 }
 
+// SetRemoteLinks enables to link builds and deployments to issues using remote links, instead of the builds and deployments APIs.
+// The client id and secret are then used as the username and API token, or as personal access token if there is no username.
+func (jc *JiraConfiguration) SetRemoteLinks(remoteLinks bool) *JiraConfiguration {
+	jc.remoteLinks = remoteLinks
+	jc.tokenAuth = jc.tokenAuth || remoteLinks
+	return jc
+}
+
+// SetTokenAuth enables to use the client id and secret as the username and API token, or as personal access token if there is no
+// username, instead of as OAuth credentials.
+func (jc *JiraConfiguration) SetTokenAuth(tokenAuth bool) *JiraConfiguration {
+	jc.tokenAuth = tokenAuth
+	return jc
+}
+
 func (jc *JiraConfiguration) ValidateJiraConfiguration() (err error) {
 	if jc.jiraUrl == "" {
 		log.Debug("Loading Jira details from integration ", jc.jiraID)
@@ -59,7 +76,8 @@ func (jc *JiraConfiguration) ValidateJiraConfiguration() (err error) {
 		jc.jiraSecret = os.Getenv("int_" + jc.jiraID + "_token")
 	}
 
-	if jc.jiraUrl == "" || jc.jiraClientId == "" || jc.jiraSecret == "" {
+	// Personal access tokens, that can be used for remote links, don't require a username.
+	if jc.jiraUrl == "" || (jc.jiraClientId == "" && !jc.tokenAuth) || jc.jiraSecret == "" {
 		return errorutils.CheckErrorf("Missing Jira details")
 	}
 
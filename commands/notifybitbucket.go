@@ -8,6 +8,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/marvelution/ext-build-info/services"
 	"github.com/marvelution/ext-build-info/services/bitbucket"
+	"github.com/marvelution/ext-build-info/services/ci"
 	"os"
 )
 
@@ -33,11 +34,16 @@ func (cmd *NotifyBitbucketCommand) SetBitbucketConfiguration(bitbucketConfigurat
 func (cmd *NotifyBitbucketCommand) Run() error {
 	log.Info("Collecting build-info to send to Bitbucket.")
 
+	environment := ci.Detect()
+	// The message is composed from the pipeline report of the run, which is only available in JFrog Pipelines.
+	if environment.Provider != ci.JFrogPipelines {
+		return errorutils.CheckErrorf("Notifying Bitbucket is only supported in runs of %s", ci.JFrogPipelines)
+	}
 	pipelinesService, err := services.NewPipelinesService(*cmd.bitbucketConfiguration.serverDetails)
 	if err != nil {
 		return err
 	}
-	pipelineReport, err := pipelinesService.GetPipelineReport(os.Getenv("run_id"), cmd.bitbucketConfiguration.includePrePostRunSteps)
+	pipelineReport, err := pipelinesService.GetPipelineReport(environment.RunId, cmd.bitbucketConfiguration.includePrePostRunSteps)
 	if err != nil {
 		return err
 	}
@@ -59,7 +65,7 @@ func (cmd *NotifyBitbucketCommand) Run() error {
 				Description: fmt.Sprintf("%d tests; %d succeeded, %d skipped, %d failed, %d errored",
 					testReport.TotalTests, testReport.TotalPassing, testReport.TotalSkipped, testReport.TotalFailures, testReport.TotalErrors),
 				Refname:   shaDataMap["branchName"].(string),
-				Url:       os.Getenv("JFROG_CLI_BUILD_URL"),
+				Url:       environment.RunUrl,
 				State:     bitbucket.GetState(pipelineReport.State),
 				CreatedOn: pipelineReport.StartedAt,
 				UpdatedOn: pipelineReport.EndedAt,
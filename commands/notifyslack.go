@@ -11,6 +11,7 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/ci"
 	"github.com/marvelution/ext-build-info/services/common"
 	"net/http"
 	"os"
@@ -39,11 +40,16 @@ func (cmd *NotifySlackCommand) SetSlackConfiguration(slackConfiguration *SlackCo
 func (cmd *NotifySlackCommand) Run() error {
 	log.Info("Collecting build-info to send to Slack.")
 
+	environment := ci.Detect()
+	// The message is composed from the pipeline report of the run, which is only available in JFrog Pipelines.
+	if environment.Provider != ci.JFrogPipelines {
+		return errorutils.CheckErrorf("Notifying Slack is only supported in runs of %s", ci.JFrogPipelines)
+	}
 	pipelinesService, err := services.NewPipelinesService(*cmd.slackConfiguration.serverDetails)
 	if err != nil {
 		return err
 	}
-	pipelineReport, err := pipelinesService.GetPipelineReport(os.Getenv("run_id"), cmd.slackConfiguration.includePrePostRunSteps)
+	pipelineReport, err := pipelinesService.GetPipelineReport(environment.RunId, cmd.slackConfiguration.includePrePostRunSteps)
 	if err != nil {
 		return err
 	}
@@ -63,7 +69,7 @@ func (cmd *NotifySlackCommand) Run() error {
 			Text: SlackText{
 				Type: "mrkdwn",
 				Text: fmt.Sprintf("%s <%s|%s #%d> *%s*",
-					icon, os.Getenv("JFROG_CLI_BUILD_URL"), pipelineReport.Name, pipelineReport.RunNumber, pipelineReport.State),
+					icon, environment.RunUrl, pipelineReport.Name, pipelineReport.RunNumber, pipelineReport.State),
 			},
 		}},
 		Attachments: []SlackAttachment{},
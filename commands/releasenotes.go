@@ -0,0 +1,373 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	utilsconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	ReleaseNotesMarkdown = "markdown"
+	ReleaseNotesHtml     = "html"
+	ReleaseNotesJson     = "json"
+
+	// ReleaseNotesOtherGroup is the group of issues that don't have a value for the field the issues are grouped by.
+	ReleaseNotesOtherGroup = "Other"
+
+	markdownReleaseNotesTemplate = `# Release notes {{.Name}} {{.ToBuild}}
+{{range .Groups}}
+## {{.Name}}
+{{range .Issues}}
+* {{if .Url}}[{{.Key}}]({{.Url}}){{else}}{{.Key}}{{end}}{{if .Summary}} {{.Summary}}{{end}}{{end}}
+{{end}}{{if .Commits}}
+## Commits
+{{range .Commits}}
+* {{short .Revision}} {{.Subject}}{{end}}
+{{end}}`
+
+	htmlReleaseNotesTemplate = `<h1>Release notes {{.Name}} {{.ToBuild}}</h1>
+{{range .Groups}}<h2>{{.Name}}</h2>
+<ul>
+{{range .Issues}}<li>{{if .Url}}<a href="{{.Url}}">{{.Key}}</a>{{else}}{{.Key}}{{end}}{{if .Summary}} {{.Summary}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{if .Commits}}<h2>Commits</h2>
+<ul>
+{{range .Commits}}<li><code>{{short .Revision}}</code> {{.Subject}}</li>
+{{end}}</ul>
+{{end}}`
+)
+
+var releaseNotesFuncs = map[string]any{
+	"short": func(revision string) string {
+		if len(revision) > 7 {
+			return revision[:7]
+		}
+		return revision
+	},
+	"join": strings.Join,
+}
+
+// ReleaseNotes is the data that release notes are rendered from, the JSON format and user templates use the same structure.
+type ReleaseNotes struct {
+	Name      string               `json:"name"`
+	FromBuild string               `json:"fromBuild,omitempty"`
+	ToBuild   string               `json:"toBuild"`
+	Builds    []ReleaseNotesBuild  `json:"builds"`
+	Groups    []*ReleaseNotesGroup `json:"groups"`
+	Issues    []ReleaseNotesIssue  `json:"issues"`
+	Commits   []ReleaseNotesCommit `json:"commits"`
+}
+
+type ReleaseNotesBuild struct {
+	Number  string `json:"number"`
+	Started string `json:"started,omitempty"`
+	Url     string `json:"url,omitempty"`
+}
+
+type ReleaseNotesGroup struct {
+	Name   string              `json:"name"`
+	Issues []ReleaseNotesIssue `json:"issues"`
+}
+
+type ReleaseNotesIssue struct {
+	Key          string            `json:"key"`
+	Summary      string            `json:"summary,omitempty"`
+	Url          string            `json:"url,omitempty"`
+	Relationship string            `json:"relationship,omitempty"`
+	Fields       map[string]string `json:"fields,omitempty"`
+	Build        string            `json:"build"`
+}
+
+type ReleaseNotesCommit struct {
+	Revision string `json:"revision"`
+	Subject  string `json:"subject,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Url      string `json:"url,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Build    string `json:"build"`
+}
+
+type ReleaseNotesCommand struct {
+	buildConfiguration        *utils.BuildConfiguration
+	releaseNotesConfiguration *ReleaseNotesConfiguration
+}
+
+func NewReleaseNotesCommand() *ReleaseNotesCommand {
+	return &ReleaseNotesCommand{}
+}
+
+func (cmd *ReleaseNotesCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *ReleaseNotesCommand {
+	cmd.buildConfiguration = buildConfiguration
+	return cmd
+}
+
+func (cmd *ReleaseNotesCommand) SetReleaseNotesConfiguration(releaseNotesConfiguration *ReleaseNotesConfiguration) *ReleaseNotesCommand {
+	cmd.releaseNotesConfiguration = releaseNotesConfiguration
+	return cmd
+}
+
+func (cmd *ReleaseNotesCommand) Run() error {
+	config := cmd.releaseNotesConfiguration
+	buildName, err := cmd.buildConfiguration.GetBuildName()
+	if err != nil {
+		return err
+	}
+	buildNumber, err := cmd.buildConfiguration.GetBuildNumber()
+	if err != nil {
+		return err
+	}
+	toBuild, err := strconv.ParseInt(buildNumber, 10, 64)
+	if err != nil {
+		return errorutils.CheckErrorf("Build number %s is not numeric", buildNumber)
+	}
+	fromBuild := toBuild - 1
+	if config.fromBuild != "" {
+		if fromBuild, err = strconv.ParseInt(config.fromBuild, 10, 64); err != nil {
+			return errorutils.CheckErrorf("Build number %s is not numeric", config.fromBuild)
+		}
+	}
+
+	log.Info(fmt.Sprintf("Collecting release notes of build range %d (exclusive) and %d (inclusive)", fromBuild, toBuild))
+	buildInfoService, err := services.CreateExtBuildInfoService(config.serverDetails)
+	if err != nil {
+		return err
+	}
+	buildInfos, err := buildInfoService.GetBuildInfosInRange(cmd.buildConfiguration, fromBuild, toBuild, config.branch)
+	if err != nil {
+		return err
+	}
+
+	releaseNotes := config.getReleaseNotes(buildName, strconv.FormatInt(fromBuild, 10), buildNumber, *buildInfos)
+	log.Info(fmt.Sprintf("Collected %d issues and %d commits from %d builds", len(releaseNotes.Issues), len(releaseNotes.Commits),
+		len(releaseNotes.Builds)))
+
+	var writer io.Writer = os.Stdout
+	if config.output != "" && config.output != "-" {
+		file, err := os.Create(config.output)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	return config.render(writer, releaseNotes)
+}
+
+type ReleaseNotesConfiguration struct {
+	serverID      string
+	serverDetails *utilsconfig.ServerDetails
+	fromBuild     string
+	branch        string
+	format        string
+	templateFile  string
+	groupBy       string
+	output        string
+}
+
+func (rc *ReleaseNotesConfiguration) SetServerID(serverID string) *ReleaseNotesConfiguration {
+	rc.serverID = serverID
+	return rc
+}
+
+// SetFromBuild sets the build number, exclusive, that the release notes start from, defaults to the previous build number.
+func (rc *ReleaseNotesConfiguration) SetFromBuild(fromBuild string) *ReleaseNotesConfiguration {
+	rc.fromBuild = fromBuild
+	return rc
+}
+
+// SetBranch limits the builds to builds of the branch, all builds are included if not set.
+func (rc *ReleaseNotesConfiguration) SetBranch(branch string) *ReleaseNotesConfiguration {
+	rc.branch = branch
+	return rc
+}
+
+func (rc *ReleaseNotesConfiguration) SetFormat(format string) *ReleaseNotesConfiguration {
+	rc.format = strings.ToLower(format)
+	return rc
+}
+
+// SetTemplateFile sets the Go template used to render the release notes, instead of the template of the format.
+func (rc *ReleaseNotesConfiguration) SetTemplateFile(templateFile string) *ReleaseNotesConfiguration {
+	rc.templateFile = templateFile
+	return rc
+}
+
+// SetGroupBy sets the issue field, as recorded by collect-issues, to group the issues by.
+func (rc *ReleaseNotesConfiguration) SetGroupBy(groupBy string) *ReleaseNotesConfiguration {
+	rc.groupBy = groupBy
+	return rc
+}
+
+// SetOutput sets the file to write the release notes to, the release notes are written to stdout if not set, or set to -.
+func (rc *ReleaseNotesConfiguration) SetOutput(output string) *ReleaseNotesConfiguration {
+	rc.output = output
+	return rc
+}
+
+func (rc *ReleaseNotesConfiguration) ValidateReleaseNotesConfiguration() (err error) {
+	if rc.format == "" {
+		rc.format = ReleaseNotesMarkdown
+	}
+	if rc.format != ReleaseNotesMarkdown && rc.format != ReleaseNotesHtml && rc.format != ReleaseNotesJson {
+		return errorutils.CheckErrorf("Unsupported release notes format %s, supported formats are %s, %s and %s", rc.format,
+			ReleaseNotesMarkdown, ReleaseNotesHtml, ReleaseNotesJson)
+	}
+
+	// If no server-id provided, use default server.
+	serverDetails, err := utilsconfig.GetSpecificConfig(rc.serverID, true, false)
+	if err != nil {
+		return err
+	}
+	rc.serverDetails = serverDetails
+	return nil
+}
+
+// Returns the release notes of the builds, issues aggregated from earlier builds are left out since they are part of the
+// release notes of those builds.
+func (rc *ReleaseNotesConfiguration) getReleaseNotes(name, fromBuild, toBuild string, buildInfos []buildinfo.BuildInfo) *ReleaseNotes {
+	releaseNotes := &ReleaseNotes{
+		Name:      name,
+		FromBuild: fromBuild,
+		ToBuild:   toBuild,
+		Builds:    []ReleaseNotesBuild{},
+		Groups:    []*ReleaseNotesGroup{},
+		Issues:    []ReleaseNotesIssue{},
+		Commits:   []ReleaseNotesCommit{},
+	}
+	issueKeys := map[string]bool{}
+	revisions := map[string]bool{}
+	for _, buildInfo := range buildInfos {
+		releaseNotes.Builds = append(releaseNotes.Builds, ReleaseNotesBuild{Number: buildInfo.Number, Started: buildInfo.Started,
+			Url: buildInfo.BuildUrl})
+		if buildInfo.Issues != nil {
+			for _, issue := range buildInfo.Issues.AffectedIssues {
+				if issue.Aggregated || issueKeys[issue.Key] {
+					continue
+				}
+				issueKeys[issue.Key] = true
+				releaseNotes.Issues = append(releaseNotes.Issues, ReleaseNotesIssue{
+					Key:          issue.Key,
+					Summary:      issue.Summary,
+					Url:          issue.Url,
+					Relationship: getIssueRelationship(buildInfo.Properties, issue.Key).Relationship,
+					Fields:       getIssueFields(buildInfo.Properties, issue.Key),
+					Build:        buildInfo.Number,
+				})
+			}
+		}
+		for _, vcs := range buildInfo.VcsList {
+			if vcs.Revision == "" || revisions[vcs.Revision] {
+				continue
+			}
+			revisions[vcs.Revision] = true
+			subject, _, _ := strings.Cut(strings.TrimSpace(vcs.Message), "\n")
+			releaseNotes.Commits = append(releaseNotes.Commits, ReleaseNotesCommit{
+				Revision: vcs.Revision,
+				Subject:  subject,
+				Message:  vcs.Message,
+				Url:      vcs.Url,
+				Branch:   vcs.Branch,
+				Build:    buildInfo.Number,
+			})
+		}
+	}
+	releaseNotes.Groups = rc.getGroups(releaseNotes.Issues)
+	return releaseNotes
+}
+
+// Returns the issues grouped by the value of the group by field, in order of appearance. Issues with multiple values, like
+// components, are part of each group. All issues are in a single group if no group by field is set.
+func (rc *ReleaseNotesConfiguration) getGroups(issues []ReleaseNotesIssue) []*ReleaseNotesGroup {
+	var groups []*ReleaseNotesGroup
+	groupsByName := map[string]*ReleaseNotesGroup{}
+	var other *ReleaseNotesGroup
+	for _, issue := range issues {
+		names := []string{"Issues"}
+		if rc.groupBy != "" {
+			names = strings.Split(issue.Fields[rc.groupBy], ",")
+		}
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name == "" {
+				if other == nil {
+					other = &ReleaseNotesGroup{Name: ReleaseNotesOtherGroup}
+				}
+				other.Issues = append(other.Issues, issue)
+				continue
+			}
+			group, found := groupsByName[name]
+			if !found {
+				group = &ReleaseNotesGroup{Name: name}
+				groupsByName[name] = group
+				groups = append(groups, group)
+			}
+			group.Issues = append(group.Issues, issue)
+		}
+	}
+	if other != nil {
+		groups = append(groups, other)
+	}
+	if groups == nil {
+		groups = []*ReleaseNotesGroup{}
+	}
+	return groups
+}
+
+// Renders the release notes using the user template, or the template of the format. HTML is escaped using html/template.
+func (rc *ReleaseNotesConfiguration) render(writer io.Writer, releaseNotes *ReleaseNotes) error {
+	if rc.format == ReleaseNotesJson && rc.templateFile == "" {
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return errorutils.CheckError(encoder.Encode(releaseNotes))
+	}
+
+	text := markdownReleaseNotesTemplate
+	if rc.format == ReleaseNotesHtml {
+		text = htmlReleaseNotesTemplate
+	}
+	if rc.templateFile != "" {
+		content, err := os.ReadFile(rc.templateFile)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		text = string(content)
+	}
+
+	if rc.format == ReleaseNotesHtml {
+		tmpl, err := htmltemplate.New("release-notes").Funcs(releaseNotesFuncs).Parse(text)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		return errorutils.CheckError(tmpl.Execute(writer, releaseNotes))
+	}
+	tmpl, err := template.New("release-notes").Funcs(releaseNotesFuncs).Parse(text)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(tmpl.Execute(writer, releaseNotes))
+}
+
+// Returns the issue fields as recorded in the build-info properties by collect-issues.
+func getIssueFields(properties map[string]string, key string) map[string]string {
+	fields := map[string]string{}
+	prefix := IssuesPropertyPrefix + key + "."
+	for name, value := range properties {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if field := strings.TrimPrefix(name, prefix); field != "relationship" && field != "sources" {
+			fields[field] = value
+		}
+	}
+	return fields
+}
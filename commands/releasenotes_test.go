@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"encoding/json"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/marvelution/ext-build-info/services/jira"
+	"reflect"
+	"testing"
+)
+
+// Returns a build-info with the issues, and their fields recorded as properties like collect-issues does for --issue-fields
+// type,components.
+func newReleaseNotesTestBuildInfo(t *testing.T, jiraFields map[string]string) buildinfo.BuildInfo {
+	fieldIds := map[string]string{"type": "issuetype", "components": "components"}
+	buildInfo := buildinfo.BuildInfo{Name: "app", Number: "2", Issues: &buildinfo.Issues{}, Properties: map[string]string{}}
+	for _, key := range []string{"ABC-1", "ABC-2", "ABC-3"} {
+		fields := &jira.IssueFields{}
+		if err := json.Unmarshal([]byte(jiraFields[key]), fields); err != nil {
+			t.Fatal(err)
+		}
+		buildInfo.Issues.AffectedIssues = append(buildInfo.Issues.AffectedIssues, buildinfo.AffectedIssue{Key: key, Summary: fields.Summary})
+		buildInfo.Properties[IssuesPropertyPrefix+key+".relationship"] = RelationshipFixed
+		buildInfo.Properties[IssuesPropertyPrefix+key+".sources"] = SourceCommitMessage
+		for field, id := range fieldIds {
+			if value := fields.GetValue(id); value != "" {
+				buildInfo.Properties[IssuesPropertyPrefix+key+"."+field] = value
+			}
+		}
+	}
+	return buildInfo
+}
+
+func TestReleaseNotesGetGroups(t *testing.T) {
+	buildInfo := newReleaseNotesTestBuildInfo(t, map[string]string{
+		"ABC-1": `{"summary": "First", "issuetype": {"name": "Bug"}, "components": [{"name": "api"}, {"name": "ui"}]}`,
+		"ABC-2": `{"summary": "Second", "issuetype": {"name": "Story"}, "components": [{"name": "ui"}]}`,
+		"ABC-3": `{"summary": "Third", "issuetype": {"name": "Bug"}, "components": []}`,
+	})
+	tests := []struct {
+		name       string
+		groupBy    string
+		buildInfos []buildinfo.BuildInfo
+		groups     map[string][]string
+		names      []string
+	}{
+		{"no issues", "type", []buildinfo.BuildInfo{{Name: "app", Number: "2"}}, map[string][]string{}, []string{}},
+		{"no group by", "", []buildinfo.BuildInfo{buildInfo}, map[string][]string{"Issues": {"ABC-1", "ABC-2", "ABC-3"}},
+			[]string{"Issues"}},
+		{"group by", "type", []buildinfo.BuildInfo{buildInfo}, map[string][]string{"Bug": {"ABC-1", "ABC-3"}, "Story": {"ABC-2"}},
+			[]string{"Bug", "Story"}},
+		{"group by multiple values", "components", []buildinfo.BuildInfo{buildInfo},
+			map[string][]string{"api": {"ABC-1"}, "ui": {"ABC-1", "ABC-2"}, ReleaseNotesOtherGroup: {"ABC-3"}},
+			[]string{"api", "ui", ReleaseNotesOtherGroup}},
+		{"field that is not recorded", "priority", []buildinfo.BuildInfo{buildInfo},
+			map[string][]string{ReleaseNotesOtherGroup: {"ABC-1", "ABC-2", "ABC-3"}}, []string{ReleaseNotesOtherGroup}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &ReleaseNotesConfiguration{groupBy: test.groupBy}
+			releaseNotes := config.getReleaseNotes("app", "1", "2", test.buildInfos)
+			names := []string{}
+			groups := map[string][]string{}
+			for _, group := range releaseNotes.Groups {
+				names = append(names, group.Name)
+				for _, issue := range group.Issues {
+					groups[group.Name] = append(groups[group.Name], issue.Key)
+				}
+			}
+			if !reflect.DeepEqual(names, test.names) {
+				t.Errorf("getGroups() returned groups %v, want %v", names, test.names)
+			}
+			if !reflect.DeepEqual(groups, test.groups) {
+				t.Errorf("getGroups() = %v, want %v", groups, test.groups)
+			}
+		})
+	}
+}
+
+func TestGetIssueFields(t *testing.T) {
+	buildInfo := newReleaseNotesTestBuildInfo(t, map[string]string{
+		"ABC-1": `{"summary": "First", "issuetype": {"name": "Bug"}, "components": [{"name": "api"}, {"name": "ui"}]}`,
+		"ABC-2": `{"summary": "Second", "issuetype": {"name": "Story"}}`,
+		"ABC-3": `{"summary": "Third"}`,
+	})
+	tests := []struct {
+		key    string
+		fields map[string]string
+	}{
+		{"ABC-1", map[string]string{"type": "Bug", "components": "api,ui"}},
+		{"ABC-2", map[string]string{"type": "Story"}},
+		{"ABC-3", map[string]string{}},
+		{"ABC-4", map[string]string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			if fields := getIssueFields(buildInfo.Properties, test.key); !reflect.DeepEqual(fields, test.fields) {
+				t.Errorf("getIssueFields(%s) = %v, want %v", test.key, fields, test.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/common"
+	"github.com/marvelution/ext-build-info/services/jira"
+	"strconv"
+	"strings"
+)
+
+const RemoteLinkApplicationType = "com.jfrog.pipelines"
+
+// RemoteLink holds the details of a build or deployment to link to from issues.
+type RemoteLink struct {
+	// Id identifies the build or deployment, links with the same id are updated instead of created.
+	Id      string
+	Title   string
+	Summary string
+	Url     string
+	State   common.State
+	// Relationship describes the relationship of the issue with the build or deployment, like builds or deployments.
+	Relationship string
+}
+
+// Creates or updates a remote link to the build or deployment on each of the issues, this is an alternative to the builds and
+// deployments APIs that are only available on Jira Cloud.
+func sendRemoteLinks(jiraConfig *JiraConfiguration, issueKeys []string, link RemoteLink) error {
+	client, err := services.NewJiraService(jiraConfig.jiraUrl, jiraConfig.jiraClientId, jiraConfig.jiraSecret)
+	if err != nil {
+		return err
+	}
+	client.SetDryRun(jiraConfig.dryRun)
+
+	stateIcon := &jira.RemoteLinkIcon{
+		Url16x16: client.GetUrl() + jira.GetStateIcon(link.State),
+		Title:    string(link.State),
+	}
+	remoteLink := jira.RemoteLink{
+		GlobalId:     "system=" + RemoteLinkApplicationType + "&id=" + link.Id,
+		Application:  &jira.RemoteLinkApplication{Type: RemoteLinkApplicationType, Name: "JFrog"},
+		Relationship: link.Relationship,
+		Object: jira.RemoteLinkObject{
+			Url:     link.Url,
+			Title:   link.Title,
+			Summary: link.Summary,
+			Icon:    stateIcon,
+			Status:  &jira.RemoteLinkStatus{Resolved: link.State == common.Successful, Icon: stateIcon},
+		},
+	}
+
+	var unknownIssueKeys []string
+	for _, issueKey := range issueKeys {
+		found, err := client.CreateOrUpdateRemoteLink(issueKey, remoteLink)
+		if err != nil {
+			return err
+		}
+		if found {
+			log.Info("Linked " + link.Title + " to issue " + issueKey)
+		} else {
+			unknownIssueKeys = append(unknownIssueKeys, issueKey)
+		}
+	}
+	if len(unknownIssueKeys) > 0 {
+		log.Warn("The following issues are unknown by Jira: " + strings.Join(unknownIssueKeys, ","))
+		if jiraConfig.failOnReject {
+			return errorutils.CheckErrorf("There are " + strconv.Itoa(len(unknownIssueKeys)) + " unknown issues")
+		}
+	}
+	return nil
+}
@@ -1,21 +1,41 @@
 package commands
 
 import (
+	"encoding/json"
+	buildinfo "github.com/jfrog/build-info-go/entities"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/ci"
 	"github.com/marvelution/ext-build-info/services/common"
 	"github.com/marvelution/ext-build-info/services/jira"
 	"github.com/marvelution/ext-build-info/util"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 )
 
+const (
+	JiraPropertyPrefix = "buildInfo.jira."
+
+	// The file in the local build directory that records the last update of the build that was sent to Jira.
+	buildUpdateFile = "jira-build-update.json"
+)
+
+// BuildUpdate records the last update of a build that was sent to Jira, so that later updates use a higher update sequence number
+// and don't regress the final state of the build.
+type BuildUpdate struct {
+	UpdateSequenceNumber int64        `json:"updateSequenceNumber"`
+	State                common.State `json:"state"`
+}
+
 type SendBuildInfoCommand struct {
 	buildConfiguration *utils.BuildConfiguration
 	jiraConfiguration  *JiraConfiguration
+	state              common.State
 }
 
 func NewSendBuildInfoCommand() *SendBuildInfoCommand {
@@ -32,66 +52,260 @@ func (cmd *SendBuildInfoCommand) SetJiraConfiguration(jiraConfiguration *JiraCon
 	return cmd
 }
 
+// SetState sets the state of the build to send, instead of the state of the pipeline run. Builds that are not final, like
+// in_progress, are sent using the local build-info, so that they can be sent before the build-info is published.
+func (cmd *SendBuildInfoCommand) SetState(state common.State) *SendBuildInfoCommand {
+	cmd.state = state
+	return cmd
+}
+
 func (cmd *SendBuildInfoCommand) Run() error {
 	log.Info("Collecting build-info to send to Jira.")
 
-	buildInfo, err := getBuildInfo(cmd.buildConfiguration, cmd.jiraConfiguration.serverDetails)
-	if err != nil {
-		return err
+	var publishedBuildInfo *services.ExtBuildInfo
+	var err error
+	if cmd.state == "" || cmd.state.IsFinal() {
+		if publishedBuildInfo, err = cmd.getPublishedBuildInfo(); err != nil {
+			return err
+		}
 	}
+	published := publishedBuildInfo != nil
 
-	if buildInfo != nil && buildInfo.Issues != nil && len(buildInfo.Issues.AffectedIssues) > 0 {
-		// We have issues, lets send the build-info
-		client, err := services.NewOAuthJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId,
-			cmd.jiraConfiguration.jiraSecret, cmd.jiraConfiguration.dryRun)
+	var buildInfo *buildinfo.BuildInfo
+	var jiraBuildInfo *jira.BuildInfo
+	if published {
+		buildInfo = &publishedBuildInfo.BuildInfo
+		pipelinesService, err := services.NewPipelinesService(*cmd.jiraConfiguration.serverDetails)
 		if err != nil {
 			return err
 		}
-
-		buildNumber, err := strconv.ParseInt(buildInfo.Number, 10, 64)
+		// The state of the pipeline run takes precedence over the state of the promotion status of the build.
+		jiraBuildInfo, err = getJiraBuildInfo(buildInfo, pipelinesService, cmd.jiraConfiguration.includePrePostRunSteps,
+			publishedBuildInfo.GetState())
 		if err != nil {
 			return err
 		}
-
-		var issueKeys []string
-		for _, issue := range buildInfo.Issues.AffectedIssues {
-			if !issue.Aggregated {
-				log.Info("Including issue " + issue.Key)
-				issueKeys = append(issueKeys, issue.Key)
-			} else {
-				log.Info("Skipping issue " + issue.Key + " since the issue is aggregated from a previous build")
-			}
+	} else {
+		log.Info("Using the local build-info, the build-info is not published")
+		if buildInfo, err = getLocalBuildInfo(cmd.buildConfiguration); err != nil {
+			return err
 		}
-		var references []jira.Reference
-		for _, vcs := range buildInfo.VcsList {
-			references = append(references, jira.Reference{
-				Commit: &jira.Commit{
-					Id:            vcs.Revision,
-					RepositoryUri: util.GetHttpsVcsUrl(vcs.Url),
-				},
-			})
+		if jiraBuildInfo, err = getJiraBuildInfo(buildInfo, nil, false, common.InProgress); err != nil {
+			return err
 		}
+	}
+	if jiraBuildInfo == nil {
+		return nil
+	}
+	if cmd.state != "" {
+		jiraBuildInfo.State = cmd.state
+	}
+	if jiraBuildInfo.Url == "" {
+		jiraBuildInfo.Url = ci.Detect().RunUrl
+	}
 
-		jiraBuildInfo := jira.BuildInfo{
-			SchemaVersion:        "1.0",
-			PipelineId:           buildInfo.Name,
-			BuildNumber:          buildNumber,
-			UpdateSequenceNumber: time.Now().UnixMilli(),
-			DisplayName:          buildInfo.Name + " #" + buildInfo.Number,
-			Url:                  buildInfo.BuildUrl,
-			State:                common.Unknown,
-			LastUpdated:          time.Now(),
-			IssueKeys:            util.RemoveDuplicate(issueKeys),
-			References:           references,
-		}
+	lastUpdate, err := cmd.getLastBuildUpdate(buildInfo)
+	if err != nil {
+		return err
+	}
+	if !applyLastBuildUpdate(jiraBuildInfo, lastUpdate) {
+		return nil
+	}
 
-		pipelinesService, err := services.NewPipelinesService(*cmd.jiraConfiguration.serverDetails)
+	if cmd.jiraConfiguration.remoteLinks {
+		err = sendRemoteLinks(cmd.jiraConfiguration, jiraBuildInfo.IssueKeys, RemoteLink{
+			Id:           util.GenerateId(buildInfo.Name + "#" + buildInfo.Number),
+			Title:        jiraBuildInfo.DisplayName,
+			Summary:      "Build " + jiraBuildInfo.DisplayName + " " + string(jiraBuildInfo.State),
+			Url:          jiraBuildInfo.Url,
+			State:        jiraBuildInfo.State,
+			Relationship: "builds",
+		})
 		if err != nil {
 			return err
 		}
-		pipelineReport, err := pipelinesService.GetPipelineReport(buildInfo.Properties["buildInfo.env.run_id"], cmd.jiraConfiguration.includePrePostRunSteps)
+		return cmd.saveBuildUpdate(jiraBuildInfo, published)
+	}
+
+	client, err := services.NewOAuthJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId,
+		cmd.jiraConfiguration.jiraSecret, cmd.jiraConfiguration.dryRun)
+	if err != nil {
+		return err
+	}
+	response, err := client.SendBuildInfo(*jiraBuildInfo)
+	if err != nil {
+		return err
+	}
+	logBuildInfoResponse(response)
+	if len(response.RejectedBuilds) > 0 && cmd.jiraConfiguration.failOnReject {
+		return errorutils.CheckErrorf("There are " + strconv.Itoa(len(response.RejectedBuilds)) + " rejected builds")
+	}
+	if len(response.AcceptedBuilds) > 0 {
+		return cmd.saveBuildUpdate(jiraBuildInfo, published)
+	}
+	return nil
+}
+
+// Returns the published build-info of the build, including its promotion statuses, or nil if it is not published.
+func (cmd *SendBuildInfoCommand) getPublishedBuildInfo() (*services.ExtBuildInfo, error) {
+	buildName, err := cmd.buildConfiguration.GetBuildName()
+	if err != nil {
+		return nil, err
+	}
+	buildNumber, err := cmd.buildConfiguration.GetBuildNumber()
+	if err != nil {
+		return nil, err
+	}
+	buildInfoService, err := services.CreateExtBuildInfoService(cmd.jiraConfiguration.serverDetails)
+	if err != nil {
+		return nil, err
+	}
+	return buildInfoService.GetBuildInfo(buildName, buildNumber, cmd.buildConfiguration.GetProject())
+}
+
+// Returns the last update of the build that was sent to Jira, recorded in the local build directory or in the build-info
+// properties, or nil if no update was sent before.
+func (cmd *SendBuildInfoCommand) getLastBuildUpdate(buildInfo *buildinfo.BuildInfo) (*BuildUpdate, error) {
+	lastUpdate := getRecordedBuildUpdate(buildInfo.Properties)
+	buildDir, err := utils.GetBuildDir(buildInfo.Name, buildInfo.Number, cmd.buildConfiguration.GetProject())
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filepath.Join(buildDir, buildUpdateFile))
+	if os.IsNotExist(err) {
+		return lastUpdate, nil
+	} else if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	localUpdate := &BuildUpdate{}
+	if err = json.Unmarshal(content, localUpdate); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if lastUpdate == nil || localUpdate.UpdateSequenceNumber > lastUpdate.UpdateSequenceNumber {
+		return localUpdate, nil
+	}
+	return lastUpdate, nil
+}
+
+// Records the update of the build in the local build directory, and in the build-info properties if the build-info is not
+// published yet, so that the update is known to later updates on other agents.
+func (cmd *SendBuildInfoCommand) saveBuildUpdate(jiraBuildInfo *jira.BuildInfo, published bool) error {
+	if cmd.jiraConfiguration.dryRun {
+		return nil
+	}
+	buildName, err := cmd.buildConfiguration.GetBuildName()
+	if err != nil {
+		return err
+	}
+	buildNumber, err := cmd.buildConfiguration.GetBuildNumber()
+	if err != nil {
+		return err
+	}
+	buildDir, err := utils.GetBuildDir(buildName, buildNumber, cmd.buildConfiguration.GetProject())
+	if err != nil {
+		return err
+	}
+	update := BuildUpdate{UpdateSequenceNumber: jiraBuildInfo.UpdateSequenceNumber, State: jiraBuildInfo.State}
+	content, err := json.Marshal(update)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = os.WriteFile(filepath.Join(buildDir, buildUpdateFile), content, 0644); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if published {
+		return nil
+	}
+	return utils.SavePartialBuildInfo(buildName, buildNumber, cmd.buildConfiguration.GetProject(), func(partial *buildinfo.Partial) {
+		partial.Env = map[string]string{
+			JiraPropertyPrefix + "updateSequenceNumber": strconv.FormatInt(update.UpdateSequenceNumber, 10),
+			JiraPropertyPrefix + "state":                string(update.State),
+		}
+	})
+}
+
+// Returns false if the update of the build is not to be sent, since it is not final and a final state was already sent to Jira.
+// Otherwise the update sequence number of the Jira build is made higher than the one of the last update of the build, if any.
+func applyLastBuildUpdate(jiraBuildInfo *jira.BuildInfo, lastUpdate *BuildUpdate) bool {
+	if lastUpdate != nil && lastUpdate.State.IsFinal() && !jiraBuildInfo.State.IsFinal() {
+		log.Info("Skipping the " + string(jiraBuildInfo.State) + " update of build " + jiraBuildInfo.DisplayName +
+			", the final state " + string(lastUpdate.State) + " was already sent to Jira")
+		return false
+	}
+	setUpdateSequenceNumber(jiraBuildInfo, lastUpdate)
+	return true
+}
+
+// Makes sure the update sequence number of the Jira build is higher than the one of the last update of the build, if any.
+func setUpdateSequenceNumber(jiraBuildInfo *jira.BuildInfo, lastUpdate *BuildUpdate) {
+	if lastUpdate != nil && jiraBuildInfo.UpdateSequenceNumber <= lastUpdate.UpdateSequenceNumber {
+		jiraBuildInfo.UpdateSequenceNumber = lastUpdate.UpdateSequenceNumber + 1
+	}
+}
+
+// Returns the update of the build recorded in the build-info properties, or nil if no update is recorded.
+func getRecordedBuildUpdate(properties map[string]string) *BuildUpdate {
+	updateSequenceNumber, err := strconv.ParseInt(properties[JiraPropertyPrefix+"updateSequenceNumber"], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &BuildUpdate{UpdateSequenceNumber: updateSequenceNumber, State: common.State(properties[JiraPropertyPrefix+"state"])}
+}
+
+// Returns the Jira build of the build-info, or nil if the build-info has no issues to send. The state and test results are taken
+// from the JFrog Pipelines run of the build, if the build was run by JFrog Pipelines, otherwise the state defaults to the given
+// state.
+func getJiraBuildInfo(buildInfo *buildinfo.BuildInfo, pipelinesService *services.PipelinesService, includePrePostRunSteps bool,
+	defaultState common.State) (*jira.BuildInfo, error) {
+	if buildInfo.Issues == nil || len(buildInfo.Issues.AffectedIssues) == 0 {
+		log.Info("Nothing to send for build " + buildInfo.Name + " #" + buildInfo.Number + ", no issue found")
+		return nil, nil
+	}
+	buildNumber, err := strconv.ParseInt(buildInfo.Number, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var issueKeys []string
+	for _, issue := range buildInfo.Issues.AffectedIssues {
+		if !issue.Aggregated {
+			log.Info("Including issue " + issue.Key)
+			issueKeys = append(issueKeys, issue.Key)
+		} else {
+			log.Info("Skipping issue " + issue.Key + " since the issue is aggregated from a previous build")
+		}
+	}
+	if len(issueKeys) == 0 {
+		log.Info("Nothing to send for build " + buildInfo.Name + " #" + buildInfo.Number + ", all issues are aggregated")
+		return nil, nil
+	}
+	var references []jira.Reference
+	for _, vcs := range buildInfo.VcsList {
+		references = append(references, jira.Reference{
+			Commit: &jira.Commit{
+				Id:            vcs.Revision,
+				RepositoryUri: util.GetHttpsVcsUrl(vcs.Url),
+			},
+		})
+	}
+
+	jiraBuildInfo := &jira.BuildInfo{
+		SchemaVersion:        "1.0",
+		PipelineId:           buildInfo.Name,
+		BuildNumber:          buildNumber,
+		UpdateSequenceNumber: time.Now().UnixMilli(),
+		DisplayName:          buildInfo.Name + " #" + buildInfo.Number,
+		Url:                  buildInfo.BuildUrl,
+		State:                defaultState,
+		LastUpdated:          time.Now(),
+		IssueKeys:            util.RemoveDuplicate(issueKeys),
+		References:           references,
+	}
+
+	if runId := buildInfo.Properties["buildInfo.env.run_id"]; runId != "" && pipelinesService != nil {
+		pipelineReport, err := pipelinesService.GetPipelineReport(runId, includePrePostRunSteps)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if pipelineReport != nil {
 			jiraBuildInfo.State = pipelineReport.State
@@ -102,31 +316,22 @@ func (cmd *SendBuildInfoCommand) Run() error {
 				NumberSkipped: pipelineReport.TestReport.TotalSkipped,
 			}
 		}
+	}
+	return jiraBuildInfo, nil
+}
 
-		response, err := client.SendBuildInfo(jiraBuildInfo)
-		if err != nil {
-			return err
-		}
-		if len(response.AcceptedBuilds) > 0 {
-			for _, build := range response.AcceptedBuilds {
-				log.Info("Build " + build.PipelineId + " #" + strconv.FormatInt(build.BuildNumber, 10) + " was accepted by Jira")
-			}
-		}
-		if len(response.RejectedBuilds) > 0 {
-			for _, build := range response.RejectedBuilds {
-				log.Warn("Build " + build.Key.PipelineId + " #" + strconv.FormatInt(build.Key.BuildNumber, 10) + " was rejected by Jira")
-				for _, buildError := range build.Errors {
-					log.Warn(" - " + buildError.Message + " (" + buildError.ErrorTraceId + ")")
-				}
-			}
-		}
-		if len(response.UnknownIssueKeys) > 0 {
-			log.Warn("The following issues are unknown by Jira: " + strings.Join(response.UnknownIssueKeys, ","))
-		}
-		if len(response.RejectedBuilds) > 0 && cmd.jiraConfiguration.failOnReject {
-			return errorutils.CheckErrorf("There are " + strconv.Itoa(len(response.RejectedBuilds)) + " rejected builds")
+// Logs the accepted and rejected builds, and the unknown issues of the response of Jira.
+func logBuildInfoResponse(response *jira.BuildInfoResponse) {
+	for _, build := range response.AcceptedBuilds {
+		log.Info("Build " + build.PipelineId + " #" + strconv.FormatInt(build.BuildNumber, 10) + " was accepted by Jira")
+	}
+	for _, build := range response.RejectedBuilds {
+		log.Warn("Build " + build.Key.PipelineId + " #" + strconv.FormatInt(build.Key.BuildNumber, 10) + " was rejected by Jira")
+		for _, buildError := range build.Errors {
+			log.Warn(" - " + buildError.Message + " (" + buildError.ErrorTraceId + ")")
 		}
 	}
-
-	return nil
+	if len(response.UnknownIssueKeys) > 0 {
+		log.Warn("The following issues are unknown by Jira: " + strings.Join(response.UnknownIssueKeys, ","))
+	}
 }
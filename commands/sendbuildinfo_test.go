@@ -0,0 +1,163 @@
+package commands
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/common"
+	"github.com/marvelution/ext-build-info/services/jira"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetRecordedBuildUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		update     *BuildUpdate
+	}{
+		{"no properties", nil, nil},
+		{"recorded", map[string]string{
+			JiraPropertyPrefix + "updateSequenceNumber": "42",
+			JiraPropertyPrefix + "state":                "in_progress",
+		}, &BuildUpdate{UpdateSequenceNumber: 42, State: common.InProgress}},
+		{"invalid sequence number", map[string]string{
+			JiraPropertyPrefix + "updateSequenceNumber": "abc",
+			JiraPropertyPrefix + "state":                "in_progress",
+		}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if update := getRecordedBuildUpdate(test.properties); !reflect.DeepEqual(update, test.update) {
+				t.Errorf("getRecordedBuildUpdate() = %v, want %v", update, test.update)
+			}
+		})
+	}
+}
+
+func TestApplyLastBuildUpdate(t *testing.T) {
+	tests := []struct {
+		name                 string
+		state                common.State
+		updateSequenceNumber int64
+		lastUpdate           *BuildUpdate
+		send                 bool
+		expected             int64
+	}{
+		{"no last update", common.InProgress, 10, nil, true, 10},
+		{"older last update", common.InProgress, 10, &BuildUpdate{UpdateSequenceNumber: 5, State: common.InProgress}, true, 10},
+		{"newer last update", common.InProgress, 10, &BuildUpdate{UpdateSequenceNumber: 20, State: common.InProgress}, true, 21},
+		{"same last update", common.Successful, 10, &BuildUpdate{UpdateSequenceNumber: 10, State: common.InProgress}, true, 11},
+		{"final after final", common.Failed, 10, &BuildUpdate{UpdateSequenceNumber: 20, State: common.Successful}, true, 21},
+		{"in progress after final", common.InProgress, 30, &BuildUpdate{UpdateSequenceNumber: 20, State: common.Successful}, false, 30},
+		{"pending after final", common.Pending, 10, &BuildUpdate{UpdateSequenceNumber: 20, State: common.Failed}, false, 10},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jiraBuildInfo := &jira.BuildInfo{DisplayName: "app #1", State: test.state, UpdateSequenceNumber: test.updateSequenceNumber}
+			if send := applyLastBuildUpdate(jiraBuildInfo, test.lastUpdate); send != test.send {
+				t.Errorf("applyLastBuildUpdate() = %v, want %v", send, test.send)
+			}
+			if jiraBuildInfo.UpdateSequenceNumber != test.expected {
+				t.Errorf("UpdateSequenceNumber = %d, want %d", jiraBuildInfo.UpdateSequenceNumber, test.expected)
+			}
+		})
+	}
+}
+
+func TestGetLastBuildUpdate(t *testing.T) {
+	buildName := "ext-build-info-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	buildDir, err := utils.GetBuildDir(buildName, "1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(buildDir) })
+
+	cmd := NewSendBuildInfoCommand().
+		SetBuildConfiguration(utils.NewBuildConfiguration(buildName, "1", "", "")).
+		SetJiraConfiguration(&JiraConfiguration{})
+	buildInfo := &buildinfo.BuildInfo{Name: buildName, Number: "1", Properties: map[string]string{
+		JiraPropertyPrefix + "updateSequenceNumber": "20",
+		JiraPropertyPrefix + "state":                "in_progress",
+	}}
+
+	lastUpdate, err := cmd.getLastBuildUpdate(buildInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (&BuildUpdate{UpdateSequenceNumber: 20, State: common.InProgress}); !reflect.DeepEqual(lastUpdate, expected) {
+		t.Errorf("getLastBuildUpdate() without local update = %v, want %v", lastUpdate, expected)
+	}
+
+	if err = cmd.saveBuildUpdate(&jira.BuildInfo{UpdateSequenceNumber: 30, State: common.Successful}, true); err != nil {
+		t.Fatal(err)
+	}
+	lastUpdate, err = cmd.getLastBuildUpdate(buildInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (&BuildUpdate{UpdateSequenceNumber: 30, State: common.Successful}); !reflect.DeepEqual(lastUpdate, expected) {
+		t.Errorf("getLastBuildUpdate() with newer local update = %v, want %v", lastUpdate, expected)
+	}
+
+	buildInfo.Properties[JiraPropertyPrefix+"updateSequenceNumber"] = "40"
+	lastUpdate, err = cmd.getLastBuildUpdate(buildInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (&BuildUpdate{UpdateSequenceNumber: 40, State: common.InProgress}); !reflect.DeepEqual(lastUpdate, expected) {
+		t.Errorf("getLastBuildUpdate() with older local update = %v, want %v", lastUpdate, expected)
+	}
+}
+
+func TestGetJiraBuildInfo(t *testing.T) {
+	buildInfo := &buildinfo.BuildInfo{
+		Name:   "app",
+		Number: "1",
+		Issues: &buildinfo.Issues{AffectedIssues: []buildinfo.AffectedIssue{
+			{Key: "ABC-1"}, {Key: "ABC-2", Aggregated: true}, {Key: "ABC-1"},
+		}},
+	}
+
+	jiraBuildInfo, err := getJiraBuildInfo(buildInfo, nil, false, common.InProgress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jiraBuildInfo.State != common.InProgress {
+		t.Errorf("State = %s, want %s", jiraBuildInfo.State, common.InProgress)
+	}
+	if expected := []string{"ABC-1"}; !reflect.DeepEqual(jiraBuildInfo.IssueKeys, expected) {
+		t.Errorf("IssueKeys = %v, want %v", jiraBuildInfo.IssueKeys, expected)
+	}
+
+	buildInfo.Issues.AffectedIssues = []buildinfo.AffectedIssue{{Key: "ABC-2", Aggregated: true}}
+	if jiraBuildInfo, err = getJiraBuildInfo(buildInfo, nil, false, common.Successful); err != nil || jiraBuildInfo != nil {
+		t.Errorf("getJiraBuildInfo() with only aggregated issues = %v, %v, want nil", jiraBuildInfo, err)
+	}
+}
+
+func TestGetJiraBuildInfoOfPublishedBuild(t *testing.T) {
+	publishedBuildInfo := &services.ExtBuildInfo{BuildInfo: buildinfo.BuildInfo{
+		Name:   "app",
+		Number: "1",
+		Issues: &buildinfo.Issues{AffectedIssues: []buildinfo.AffectedIssue{{Key: "ABC-1"}}},
+	}}
+
+	jiraBuildInfo, err := getJiraBuildInfo(&publishedBuildInfo.BuildInfo, nil, false, publishedBuildInfo.GetState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jiraBuildInfo.State != common.Successful {
+		t.Errorf("State = %s, want %s", jiraBuildInfo.State, common.Successful)
+	}
+	lastUpdate := &BuildUpdate{UpdateSequenceNumber: jiraBuildInfo.UpdateSequenceNumber, State: common.InProgress}
+	if !applyLastBuildUpdate(jiraBuildInfo, lastUpdate) {
+		t.Errorf("applyLastBuildUpdate() = false, want the in progress update moved to %s", jiraBuildInfo.State)
+	}
+	if jiraBuildInfo.UpdateSequenceNumber <= lastUpdate.UpdateSequenceNumber {
+		t.Errorf("UpdateSequenceNumber = %d, want higher than %d", jiraBuildInfo.UpdateSequenceNumber, lastUpdate.UpdateSequenceNumber)
+	}
+}
@@ -7,10 +7,11 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/ci"
+	"github.com/marvelution/ext-build-info/services/common"
 	"github.com/marvelution/ext-build-info/services/jira"
 	"github.com/marvelution/ext-build-info/services/pipelines"
 	"github.com/marvelution/ext-build-info/util"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -50,21 +51,88 @@ func (cmd *SendDeploymentInfoCommand) Run() error {
 		return err
 	}
 
-	// Get current run details
-	currentRun, err := pipelinesService.GetRun(cmd.deploymentInfo.runId)
+	currentRun, buildInfos, err := getDeployedBuildInfos(pipelinesService, cmd.buildConfiguration, cmd.jiraConfiguration, cmd.deploymentInfo)
 	if err != nil {
 		return err
 	}
+
+	var issueKeys []string
+	var buildInfo = &buildinfo.BuildInfo{}
+	for index, info := range buildInfos {
+		getIssueKeys(&info, &issueKeys)
+		if index == len(buildInfos)-1 {
+			buildInfo = &info
+		}
+	}
+
+	if len(issueKeys) > 0 {
+		// We have issues, lets send the deployment-info
+		state, _ := getDeploymentState(pipelinesService, currentRun, cmd.jiraConfiguration.includePrePostRunSteps)
+
+		jiraDeploymentInfo := cmd.deploymentInfo.GetJiraDeploymentInfo(buildInfo, issueKeys, state)
+
+		if cmd.jiraConfiguration.remoteLinks {
+			return sendRemoteLinks(cmd.jiraConfiguration, util.RemoveDuplicate(issueKeys), RemoteLink{
+				Id:           util.GenerateId(cmd.deploymentInfo.GetDisplayName() + "@" + cmd.deploymentInfo.environment),
+				Title:        cmd.deploymentInfo.GetDisplayName() + " to " + cmd.deploymentInfo.environment,
+				Summary:      jiraDeploymentInfo.Description,
+				Url:          jiraDeploymentInfo.Url,
+				State:        jiraDeploymentInfo.State,
+				Relationship: "deployments",
+			})
+		}
+
+		client, err := services.NewOAuthJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId,
+			cmd.jiraConfiguration.jiraSecret, cmd.jiraConfiguration.dryRun)
+		if err != nil {
+			return err
+		}
+		response, err := client.SendDeploymentInfo(jiraDeploymentInfo)
+		if err != nil {
+			return err
+		}
+		logDeploymentInfoResponse(response)
+		if len(response.RejectedDeployments) > 0 && cmd.jiraConfiguration.failOnReject {
+			return errorutils.CheckErrorf("There are " + strconv.Itoa(len(response.RejectedDeployments)) + " rejected deployments")
+		}
+	} else {
+		log.Info("Nothing to send, no issue found")
+	}
+
+	return nil
+}
+
+// Returns the current run, and the build-infos of the builds that are deployed by the run, these are the builds since the build that
+// was deployed by the previous run of the pipeline. If the deployment is not run by JFrog Pipelines, there is no current run and only
+// the build of the build configuration is returned.
+func getDeployedBuildInfos(pipelinesService *services.PipelinesService, buildConfiguration *utils.BuildConfiguration,
+	jiraConfiguration *JiraConfiguration, deploymentInfo *DeploymentInfo) (*pipelines.Run, []buildinfo.BuildInfo, error) {
+	if !deploymentInfo.isPipelinesRun() {
+		buildInfo, err := getBuildInfo(buildConfiguration, jiraConfiguration.serverDetails)
+		if err != nil {
+			return nil, nil, err
+		}
+		if buildInfo == nil {
+			return nil, nil, errorutils.CheckErrorf("Build-info was not found")
+		}
+		return nil, []buildinfo.BuildInfo{*buildInfo}, nil
+	}
+
+	// Get current run details
+	currentRun, err := pipelinesService.GetRun(deploymentInfo.runId)
+	if err != nil {
+		return nil, nil, err
+	}
 	// Get current run resource version details
 	triggeredByRunResourceVersionId := currentRun.StaticPropertyBag["triggeredByRunResourceVersionId"].(string)
 	currentRunResourceVersion, err := pipelinesService.GetRunResourceVersion(triggeredByRunResourceVersionId)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	// Get CreatedBy Run details
 	createdByRun, err := pipelinesService.GetRun(currentRun.ParentRunId)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	// Get Previous CreatedBy Run
 	attributes := map[string]string{
@@ -76,7 +144,7 @@ func (cmd *SendDeploymentInfoCommand) Run() error {
 	}
 	previousRun, err := pipelinesService.FindRun(attributes)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	attributes = map[string]string{
 		"runIds":        strconv.FormatInt(previousRun.Id, 10),
@@ -84,94 +152,37 @@ func (cmd *SendDeploymentInfoCommand) Run() error {
 	}
 	previousRunResourceVersion, err := pipelinesService.FindRunResourceVersion(attributes)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	firstExclusiveBuild := cmd.getBuildNumber(previousRunResourceVersion)
-	lastInclusiveBuild := cmd.getBuildNumber(currentRunResourceVersion)
+	firstExclusiveBuild := getBuildNumber(previousRunResourceVersion)
+	lastInclusiveBuild := getBuildNumber(currentRunResourceVersion)
 
 	log.Info(fmt.Sprintf("Collecting issues linked to build range %d (exclusive) and %d (inclusive)", firstExclusiveBuild,
 		lastInclusiveBuild))
 
-	var issueKeys []string
-
-	buildInfoService, err := services.CreateExtBuildInfoService(cmd.jiraConfiguration.serverDetails)
+	buildInfoService, err := services.CreateExtBuildInfoService(jiraConfiguration.serverDetails)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	buildInfos, err := buildInfoService.GetBuildInfosInRange(cmd.buildConfiguration, firstExclusiveBuild, lastInclusiveBuild, currentRunResourceVersion.PipelineSourceBranch)
+	buildInfos, err := buildInfoService.GetBuildInfosInRange(buildConfiguration, firstExclusiveBuild, lastInclusiveBuild, currentRunResourceVersion.PipelineSourceBranch)
 	if err != nil {
-		return err
-	}
-	var buildInfo = &buildinfo.BuildInfo{}
-	for index, info := range *buildInfos {
-		cmd.getIssueKeys(&info, &issueKeys)
-		if index == len(*buildInfos)-1 {
-			buildInfo = &info
-		}
+		return nil, nil, err
 	}
+	return currentRun, *buildInfos, nil
+}
 
-	if len(issueKeys) > 0 {
-		// We have issues, lets send the deployment-info
-		client, err := services.NewOAuthJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId,
-			cmd.jiraConfiguration.jiraSecret, cmd.jiraConfiguration.dryRun)
-		if err != nil {
-			return err
-		}
-
-		_, _, state, _ := pipelinesService.GetRunSteps(currentRun.Id, cmd.jiraConfiguration.includePrePostRunSteps)
-
-		jiraDeploymentInfo := jira.DeploymentInfo{
-			SchemaVersion:            "1.0",
-			DeploymentSequenceNumber: cmd.deploymentInfo.runNumber,
-			UpdateSequenceNumber:     time.Now().UnixMilli(),
-			Associations: []jira.Association{{
-				AssociationType: jira.IssueIdOrKeysAssociation,
-				Values:          util.RemoveDuplicate(issueKeys),
-			}},
-			DisplayName: cmd.deploymentInfo.GetDisplayName(),
-			Url:         cmd.deploymentInfo.url,
-			Description: "Deployment of " + buildInfo.Name + " #" + buildInfo.Number + " to " + cmd.deploymentInfo.environment,
-			LastUpdated: time.Now(),
-			State:       state,
-			Pipeline:    cmd.deploymentInfo.GetPipeline(),
-			Environment: cmd.deploymentInfo.GetEnvironment(),
-		}
-
-		response, err := client.SendDeploymentInfo(jiraDeploymentInfo)
-		if err != nil {
-			return err
-		}
-		if len(response.AcceptedDeployments) > 0 {
-			for _, deployment := range response.AcceptedDeployments {
-				log.Info("Deployment " + deployment.PipelineId + " #" + strconv.FormatInt(deployment.DeploymentSequenceNumber,
-					10) + " was accepted by Jira")
-			}
-		}
-		if len(response.RejectedDeployments) > 0 {
-			for _, deployment := range response.RejectedDeployments {
-				log.Warn("Deployment " + deployment.Key.PipelineId + " #" + strconv.FormatInt(deployment.Key.DeploymentSequenceNumber,
-					10) + " was rejected by Jira")
-				for _, deploymentError := range deployment.Errors {
-					log.Warn(" - " + deploymentError.Message + " (" + deploymentError.ErrorTraceId + ")")
-				}
-			}
-		}
-		if len(response.UnknownIssueKeys) > 0 {
-			log.Warn("The following issues are unknown by Jira: " + strings.Join(response.UnknownIssueKeys, ","))
-		}
-		if len(response.RejectedDeployments) > 0 && cmd.jiraConfiguration.failOnReject {
-			return errorutils.CheckErrorf("There are " + strconv.Itoa(len(response.RejectedDeployments)) + " rejected deployments")
-		}
-	} else {
-		log.Info("Nothing to send, no issue found")
+// Returns the state of the current run, the state is unknown if the deployment is not run by JFrog Pipelines.
+func getDeploymentState(pipelinesService *services.PipelinesService, currentRun *pipelines.Run, includePrePostRunSteps bool) (common.State, error) {
+	if currentRun == nil {
+		return common.Unknown, nil
 	}
-
-	return nil
+	_, _, state, err := pipelinesService.GetRunSteps(currentRun.Id, includePrePostRunSteps)
+	return state, err
 }
 
-func (cmd *SendDeploymentInfoCommand) getBuildNumber(resourceVersion *pipelines.RunResourceVersion) int64 {
+func getBuildNumber(resourceVersion *pipelines.RunResourceVersion) int64 {
 	var buildNumber string
 	if resourceVersion.ResourceVersionContentPropertyBag["buildNumber"] != nil {
 		buildNumber = resourceVersion.ResourceVersionContentPropertyBag["buildNumber"].(string)
@@ -188,7 +199,7 @@ func (cmd *SendDeploymentInfoCommand) getBuildNumber(resourceVersion *pipelines.
 	return number
 }
 
-func (cmd *SendDeploymentInfoCommand) getIssueKeys(buildInfo *buildinfo.BuildInfo, issueKeys *[]string) {
+func getIssueKeys(buildInfo *buildinfo.BuildInfo, issueKeys *[]string) {
 	if buildInfo.Issues != nil && len(buildInfo.Issues.AffectedIssues) > 0 {
 		for _, issue := range buildInfo.Issues.AffectedIssues {
 			*issueKeys = append(*issueKeys, issue.Key)
@@ -197,6 +208,7 @@ func (cmd *SendDeploymentInfoCommand) getIssueKeys(buildInfo *buildinfo.BuildInf
 }
 
 type DeploymentInfo struct {
+	provider    string
 	name        string
 	runId       int64
 	runNumber   int64
@@ -204,21 +216,70 @@ type DeploymentInfo struct {
 	environment string
 }
 
-func NewDeploymentInfo(environment string) *DeploymentInfo {
-	runId, err := strconv.ParseInt(os.Getenv("run_id"), 10, 64)
+// NewDeploymentInfo creates the deployment details of the current run, using the run details of the detected CI system.
+func NewDeploymentInfo(environment string) (*DeploymentInfo, error) {
+	ciEnvironment := ci.Detect()
+	runNumber, err := strconv.ParseInt(ciEnvironment.RunNumber, 10, 64)
 	if err != nil {
-		panic(err)
+		return nil, errorutils.CheckErrorf("Unable to determine the run number of the deployment: %s", err.Error())
 	}
-	runNumber, err := strconv.ParseInt(os.Getenv("run_number"), 10, 64)
-	if err != nil {
-		panic(err)
+	// The run id is only used to look up the state of the run and the previous deployment in JFrog Pipelines.
+	var runId int64
+	if ciEnvironment.Provider == ci.JFrogPipelines {
+		if runId, err = strconv.ParseInt(ciEnvironment.RunId, 10, 64); err != nil {
+			return nil, errorutils.CheckErrorf("Unable to determine the run id of the deployment: %s", err.Error())
+		}
 	}
 	return &DeploymentInfo{
-		name:        os.Getenv("pipeline_name"),
+		provider:    ciEnvironment.Provider,
+		name:        ciEnvironment.PipelineName,
 		runId:       runId,
 		runNumber:   runNumber,
-		url:         os.Getenv("JFROG_CLI_BUILD_URL"),
+		url:         ciEnvironment.RunUrl,
 		environment: environment,
+	}, nil
+}
+
+// Returns true if the deployment is run by JFrog Pipelines, only then the state of the run and the previous deployment are known.
+func (di *DeploymentInfo) isPipelinesRun() bool {
+	return di.provider == ci.JFrogPipelines
+}
+
+// GetJiraDeploymentInfo returns the Jira deployment of the build to the environment, associated with the issues.
+func (di *DeploymentInfo) GetJiraDeploymentInfo(buildInfo *buildinfo.BuildInfo, issueKeys []string, state common.State) jira.DeploymentInfo {
+	return jira.DeploymentInfo{
+		SchemaVersion:            "1.0",
+		DeploymentSequenceNumber: di.runNumber,
+		UpdateSequenceNumber:     time.Now().UnixMilli(),
+		Associations: []jira.Association{{
+			AssociationType: jira.IssueIdOrKeysAssociation,
+			Values:          util.RemoveDuplicate(issueKeys),
+		}},
+		DisplayName: di.GetDisplayName(),
+		Url:         di.url,
+		Description: "Deployment of " + buildInfo.Name + " #" + buildInfo.Number + " to " + di.environment,
+		LastUpdated: time.Now(),
+		State:       state,
+		Pipeline:    di.GetPipeline(),
+		Environment: di.GetEnvironment(),
+	}
+}
+
+// Logs the accepted and rejected deployments, and the unknown issues of the response of Jira.
+func logDeploymentInfoResponse(response *jira.DeploymentInfoResponse) {
+	for _, deployment := range response.AcceptedDeployments {
+		log.Info("Deployment " + deployment.PipelineId + " #" + strconv.FormatInt(deployment.DeploymentSequenceNumber, 10) +
+			" was accepted by Jira")
+	}
+	for _, deployment := range response.RejectedDeployments {
+		log.Warn("Deployment " + deployment.Key.PipelineId + " #" + strconv.FormatInt(deployment.Key.DeploymentSequenceNumber, 10) +
+			" was rejected by Jira")
+		for _, deploymentError := range deployment.Errors {
+			log.Warn(" - " + deploymentError.Message + " (" + deploymentError.ErrorTraceId + ")")
+		}
+	}
+	if len(response.UnknownIssueKeys) > 0 {
+		log.Warn("The following issues are unknown by Jira: " + strings.Join(response.UnknownIssueKeys, ","))
 	}
 }
 
@@ -227,9 +288,11 @@ func (di *DeploymentInfo) GetDisplayName() string {
 }
 
 func (di *DeploymentInfo) GetPipeline() jira.Pipeline {
+	url := di.url
 	regex := regexp.MustCompile("(.*)/([0-9]*)/?(.*)\\?(.*)")
-	parts := regex.FindAllStringSubmatch(di.url, -1)
-	url := parts[0][1] + "?" + parts[0][4]
+	if parts := regex.FindAllStringSubmatch(di.url, -1); parts != nil {
+		url = parts[0][1] + "?" + parts[0][4]
+	}
 	return jira.Pipeline{
 		Id:          util.GenerateId(di.name),
 		DisplayName: di.name,
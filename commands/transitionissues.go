@@ -0,0 +1,204 @@
+package commands
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/common"
+	"github.com/marvelution/ext-build-info/services/jira"
+	"github.com/marvelution/ext-build-info/util"
+	"strconv"
+	"strings"
+)
+
+type TransitionIssuesCommand struct {
+	buildConfiguration *utils.BuildConfiguration
+	jiraConfiguration  *JiraConfiguration
+	deploymentInfo     *DeploymentInfo
+	transitions        map[string]string
+	comment            bool
+	onlyFixed          bool
+}
+
+func NewTransitionIssuesCommand() *TransitionIssuesCommand {
+	return &TransitionIssuesCommand{}
+}
+
+func (cmd *TransitionIssuesCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *TransitionIssuesCommand {
+	cmd.buildConfiguration = buildConfiguration
+	return cmd
+}
+
+func (cmd *TransitionIssuesCommand) SetJiraConfiguration(jiraConfiguration *JiraConfiguration) *TransitionIssuesCommand {
+	cmd.jiraConfiguration = jiraConfiguration
+	return cmd
+}
+
+// SetDeploymentInfo enables to transition the issues of a deployment, instead of the issues of a build.
+func (cmd *TransitionIssuesCommand) SetDeploymentInfo(deploymentInfo *DeploymentInfo) *TransitionIssuesCommand {
+	cmd.deploymentInfo = deploymentInfo
+	return cmd
+}
+
+// SetTransitions sets the transition to apply per build state, or per deployment environment name or type.
+func (cmd *TransitionIssuesCommand) SetTransitions(transitions map[string]string) *TransitionIssuesCommand {
+	cmd.transitions = map[string]string{}
+	for key, transition := range transitions {
+		cmd.transitions[strings.ToLower(key)] = transition
+	}
+	return cmd
+}
+
+func (cmd *TransitionIssuesCommand) SetComment(comment bool) *TransitionIssuesCommand {
+	cmd.comment = comment
+	return cmd
+}
+
+func (cmd *TransitionIssuesCommand) SetOnlyFixed(onlyFixed bool) *TransitionIssuesCommand {
+	cmd.onlyFixed = onlyFixed
+	return cmd
+}
+
+func (cmd *TransitionIssuesCommand) Run() error {
+	pipelinesService, err := services.NewPipelinesService(*cmd.jiraConfiguration.serverDetails)
+	if err != nil {
+		return err
+	}
+
+	var issueKeys []string
+	var state common.State
+	var transitionKeys []string
+	var title, url string
+	if cmd.deploymentInfo != nil {
+		log.Info("Collecting deployment issues to transition in Jira.")
+		currentRun, buildInfos, err := getDeployedBuildInfos(pipelinesService, cmd.buildConfiguration, cmd.jiraConfiguration, cmd.deploymentInfo)
+		if err != nil {
+			return err
+		}
+		for _, buildInfo := range buildInfos {
+			issueKeys = append(issueKeys, cmd.getIssueKeys(&buildInfo, true)...)
+		}
+		state, err = getDeploymentState(pipelinesService, currentRun, cmd.jiraConfiguration.includePrePostRunSteps)
+		if err != nil {
+			return err
+		}
+		// Deployments only transition issues once the issues are actually deployed.
+		if state == common.Successful {
+			environment := cmd.deploymentInfo.GetEnvironment()
+			transitionKeys = []string{environment.DisplayName, string(environment.Type)}
+		}
+		title = "Deployment " + cmd.deploymentInfo.GetDisplayName() + " to " + cmd.deploymentInfo.environment
+		url = cmd.deploymentInfo.url
+	} else {
+		log.Info("Collecting build issues to transition in Jira.")
+		buildInfo, err := getBuildInfo(cmd.buildConfiguration, cmd.jiraConfiguration.serverDetails)
+		if err != nil {
+			return err
+		}
+		if buildInfo == nil {
+			return errorutils.CheckErrorf("Build-info was not found")
+		}
+		issueKeys = cmd.getIssueKeys(buildInfo, false)
+		state = common.Unknown
+		// Only builds run by JFrog Pipelines have a pipeline report with the state of the build.
+		if runId := buildInfo.Properties["buildInfo.env.run_id"]; runId != "" {
+			pipelineReport, err := pipelinesService.GetPipelineReport(runId, cmd.jiraConfiguration.includePrePostRunSteps)
+			if err != nil {
+				return err
+			}
+			if pipelineReport != nil {
+				state = pipelineReport.State
+			}
+		}
+		transitionKeys = []string{string(state)}
+		title = "Build " + buildInfo.Name + " #" + buildInfo.Number
+		url = buildInfo.BuildUrl
+	}
+
+	var transitionName string
+	for _, key := range transitionKeys {
+		if transition, found := cmd.transitions[strings.ToLower(key)]; found {
+			transitionName = transition
+			break
+		}
+	}
+	issueKeys = util.RemoveDuplicate(issueKeys)
+	if len(issueKeys) == 0 || (transitionName == "" && !cmd.comment) {
+		log.Info("Nothing to do, no issues or transition found for " + title + " (" + string(state) + ")")
+		return nil
+	}
+
+	client, err := services.NewJiraService(cmd.jiraConfiguration.jiraUrl, cmd.jiraConfiguration.jiraClientId, cmd.jiraConfiguration.jiraSecret)
+	if err != nil {
+		return err
+	}
+	client.SetDryRun(cmd.jiraConfiguration.dryRun)
+
+	var failedIssueKeys []string
+	for _, issueKey := range issueKeys {
+		if transitionName != "" {
+			if err := cmd.transitionIssue(client, issueKey, transitionName); err != nil {
+				log.Warn("Failed to transition issue " + issueKey + ": " + err.Error())
+				failedIssueKeys = append(failedIssueKeys, issueKey)
+				continue
+			}
+		}
+		if cmd.comment {
+			comment := title + " was " + strings.ReplaceAll(string(state), "_", " ")
+			if url != "" {
+				comment = "[" + title + "|" + url + "] was " + strings.ReplaceAll(string(state), "_", " ")
+			}
+			if err := client.AddComment(issueKey, comment); err != nil {
+				log.Warn("Failed to comment on issue " + issueKey + ": " + err.Error())
+				failedIssueKeys = append(failedIssueKeys, issueKey)
+			}
+		}
+	}
+	if len(failedIssueKeys) > 0 && cmd.jiraConfiguration.failOnReject {
+		return errorutils.CheckErrorf("There are " + strconv.Itoa(len(failedIssueKeys)) + " issues that failed to update")
+	}
+	return nil
+}
+
+// Transitions the issue using the transition with the given name, or the transition to the status with the given name.
+func (cmd *TransitionIssuesCommand) transitionIssue(client *services.JiraService, issueKey, transitionName string) error {
+	transitions, err := client.GetTransitions(issueKey)
+	if err != nil {
+		return err
+	}
+	var transition *jira.Transition
+	for i, candidate := range transitions {
+		if strings.EqualFold(candidate.Name, transitionName) || strings.EqualFold(candidate.To.Name, transitionName) {
+			transition = &transitions[i]
+			break
+		}
+	}
+	if transition == nil {
+		return errorutils.CheckErrorf("The transition %s is not available", transitionName)
+	}
+	if err := client.TransitionIssue(issueKey, transition.Id); err != nil {
+		return err
+	}
+	log.Info("Transitioned issue " + issueKey + " using " + transition.Name)
+	return nil
+}
+
+// Returns the issue keys of the build-info, only fixed issues are returned if required.
+func (cmd *TransitionIssuesCommand) getIssueKeys(buildInfo *buildinfo.BuildInfo, includeAggregated bool) []string {
+	var issueKeys []string
+	if buildInfo.Issues == nil {
+		return issueKeys
+	}
+	for _, issue := range buildInfo.Issues.AffectedIssues {
+		if issue.Aggregated && !includeAggregated {
+			log.Info("Skipping issue " + issue.Key + " since the issue is aggregated from a previous build")
+		} else if relationship := getIssueRelationship(buildInfo.Properties, issue.Key); cmd.onlyFixed && relationship.Relationship != RelationshipFixed {
+			log.Info("Skipping issue " + issue.Key + " since the issue is not fixed by build " + buildInfo.Number)
+		} else {
+			issueKeys = append(issueKeys, issue.Key)
+		}
+	}
+	return issueKeys
+}
@@ -3,6 +3,7 @@ module github.com/marvelution/ext-build-info
 go 1.19
 
 require (
+	github.com/go-git/go-git/v5 v5.6.1
 	github.com/jfrog/build-info-go v1.9.6
 	github.com/jfrog/jfrog-cli-core/v2 v2.31.1
 	github.com/jfrog/jfrog-client-go v1.28.1
@@ -25,7 +26,6 @@ require (
 	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/go-git/gcfg v1.5.0 // indirect
 	github.com/go-git/go-billy/v5 v5.4.1 // indirect
-	github.com/go-git/go-git/v5 v5.6.1 // indirect
 	github.com/golang-jwt/jwt/v4 v4.5.0 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
 	github.com/gookit/color v1.5.3 // indirect
@@ -8,8 +8,14 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/marvelution/ext-build-info/commands"
+	"github.com/marvelution/ext-build-info/services/common"
+	_ "github.com/marvelution/ext-build-info/services/tracker/github"
+	_ "github.com/marvelution/ext-build-info/services/tracker/gitlab"
+	_ "github.com/marvelution/ext-build-info/services/tracker/jira"
+	"github.com/marvelution/ext-build-info/util"
 	"os"
 	"strconv"
+	"time"
 )
 
 func main() {
@@ -48,12 +54,21 @@ func main() {
 						Description: "Tracker token to use to collect related issue from.",
 					},
 					components.StringFlag{
-						Name:        "regexp",
-						Description: "A regular expression used for matching the git commit messages.",
+						Name: "regexp",
+						Description: "Newline separated regular expressions used for matching the git commit messages and branch names. " +
+							"Named groups key, project and relation can be used to capture the issue key, project and relationship.",
+					},
+					components.StringFlag{
+						Name:        "branch-regexp",
+						Description: "Newline separated regular expressions used for matching the git branch name, instead of the regexp.",
+					},
+					components.StringFlag{
+						Name:        "message-regexp",
+						Description: "Newline separated regular expressions used for matching the git commit messages, instead of the regexp.",
 					},
 					components.StringFlag{
 						Name:         "key-group-index",
-						Description:  "The capturing group index in the regular expression used for retrieving the issue key.",
+						Description:  "The capturing group index in the regular expression used for retrieving the issue key, if there is no group named key.",
 						DefaultValue: "1",
 					},
 					components.StringFlag{
@@ -73,6 +88,59 @@ func main() {
 							"issues will be aggregated from previous builds, until a build with a RELEASE status is found. " +
 							"Build statuses are set when a build is promoted using the jf rt build-promote command.",
 					},
+					components.StringFlag{
+						Name:         "max-previous-builds",
+						Description:  "The maximum number of previous builds to read when aggregating issues, or when looking for the baseline build.",
+						DefaultValue: "100",
+					},
+					components.StringFlag{
+						Name: "baseline",
+						Description: "The previous build to collect issues since, LATEST for the latest build, BRANCH for the " +
+							"latest build of the same branch, or STATUS for the latest build with the baseline-status.",
+						DefaultValue: "LATEST",
+					},
+					components.StringFlag{
+						Name:        "baseline-status",
+						Description: "If baseline is set to STATUS, the status the previous build should have been promoted with.",
+					},
+					components.StringFlag{
+						Name:        "since-build",
+						Description: "The number of the previous build to collect issues since, this takes precedence over the baseline.",
+					},
+					components.StringFlag{
+						Name:        "include-paths",
+						Description: "Comma separated list of paths, only commits changing any of these paths are used to collect issues.",
+					},
+					components.StringFlag{
+						Name:        "exclude-paths",
+						Description: "Comma separated list of paths, commits only changing these paths are not used to collect issues.",
+					},
+					components.StringFlag{
+						Name:        "allow-projects",
+						Description: "Comma separated list of project keys, only issues of these projects are collected.",
+					},
+					components.StringFlag{
+						Name:        "deny-projects",
+						Description: "Comma separated list of project keys, issues of these projects are not collected, e.g. UTF,SHA,ISO.",
+					},
+					components.BoolFlag{
+						Name:         "discover-projects",
+						Description:  "Set to true, if you wish to only collect issues of projects that are known by the tracker.",
+						DefaultValue: false,
+					},
+					components.StringFlag{
+						Name:        "issue-fields",
+						Description: "Comma separated list of extra issue fields to add to the build-info properties, e.g. type,status,assignee.",
+					},
+					components.BoolFlag{
+						Name:         "include-submodules",
+						Description:  "Set to true, if you wish to also collect the vcs details and issues of git submodules.",
+						DefaultValue: false,
+					},
+					components.StringFlag{
+						Name:        "output",
+						Description: "The file to write the collected issues, commits and revision ranges to as JSON, use - for stdout.",
+					},
 				},
 				Arguments: []components.Argument{
 					{
@@ -85,7 +153,7 @@ func main() {
 					},
 					{
 						Name:        "path to .git",
-						Description: "Path to a directory containing the .git directory. If not specified, the .git directory is assumed to be in the current directory or in one of the parent directories.",
+						Description: "Paths to directories containing the .git directory. If not specified, the .git directory is assumed to be in the current directory or in one of the parent directories.",
 					},
 				},
 				Action: func(c *components.Context) error {
@@ -160,6 +228,17 @@ func main() {
 						Description:  "Enable to error out if any builds are rejected by Jira.",
 						DefaultValue: false,
 					},
+					components.BoolFlag{
+						Name: "remote-links",
+						Description: "Enable to link the build to issues using remote links, e.g. for Jira Server and Data Center. " +
+							"The jira-client-id and jira-secret are then used as username and API token.",
+						DefaultValue: false,
+					},
+					components.StringFlag{
+						Name: "state",
+						Description: "The state of the build to send, like in_progress at the start of the build, defaults to the " +
+							"state of the pipeline run.",
+					},
 				},
 				Arguments: []components.Argument{
 					{
@@ -219,6 +298,12 @@ func main() {
 						Description:  "Enable to error out if any builds are rejected by Jira.",
 						DefaultValue: false,
 					},
+					components.BoolFlag{
+						Name: "remote-links",
+						Description: "Enable to link the deployment to issues using remote links, e.g. for Jira Server and Data Center. " +
+							"The jira-client-id and jira-secret are then used as username and API token.",
+						DefaultValue: false,
+					},
 					components.StringFlag{
 						Name:        "environment",
 						Description: "The environment that the deployment targeted.",
@@ -238,6 +323,394 @@ func main() {
 					return sendDeploymentInfoCmd(c)
 				},
 			},
+			{
+				Name:        "transition-issues",
+				Description: "Transition the issues of a build or deployment in Jira",
+				Aliases:     []string{"ti"},
+				Flags: []components.Flag{
+					components.StringFlag{
+						Name:        "server-id",
+						Description: "Server ID configured using the config command.",
+					},
+					components.StringFlag{
+						Name:        "project",
+						Description: "Artifactory project key.",
+					},
+					components.StringFlag{
+						Name:        "jira-id",
+						Description: "Jira integration name.",
+					},
+					components.StringFlag{
+						Name:        "jira-url",
+						Description: "Jira base url.",
+					},
+					components.StringFlag{
+						Name:        "jira-username",
+						Description: "The Jira username, leave empty to use the jira-token as personal access token.",
+					},
+					components.StringFlag{
+						Name:        "jira-token",
+						Description: "The Jira API token or personal access token.",
+					},
+					components.StringFlag{
+						Name: "transitions",
+						Description: "Comma separated list of build states or deployment environments with the transition to apply, " +
+							"like successful=Ready for QA,production=Done.",
+					},
+					components.StringFlag{
+						Name:        "environment",
+						Description: "The environment that the deployment targeted, transitions the issues of the build instead if not set.",
+					},
+					components.BoolFlag{
+						Name:         "comment",
+						Description:  "Enable to add a comment linking the build or deployment to the issues.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "only-fixed",
+						Description:  "Enable to only transition issues that are fixed by the build.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "dry-run",
+						Description:  "Enable to only log what would be send to Jira.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "include-pre-post-runs",
+						Description:  "Enable to include pipeline preRun and postRun steps.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "fail-on-reject",
+						Description:  "Enable to error out if any issues failed to update.",
+						DefaultValue: false,
+					},
+				},
+				Arguments: []components.Argument{
+					{
+						Name:        "build name",
+						Description: "The name of the build.",
+					},
+					{
+						Name:        "build number",
+						Description: "The number of the build.",
+					},
+				},
+				Action: func(c *components.Context) error {
+					return transitionIssuesCmd(c)
+				},
+			},
+			{
+				Name:        "fix-version",
+				Description: "Set a Jira fix version on the issues of a build or deployment",
+				Aliases:     []string{"fv"},
+				Flags: []components.Flag{
+					components.StringFlag{
+						Name:        "server-id",
+						Description: "Server ID configured using the config command.",
+					},
+					components.StringFlag{
+						Name:        "project",
+						Description: "Artifactory project key.",
+					},
+					components.StringFlag{
+						Name:        "jira-id",
+						Description: "Jira integration name.",
+					},
+					components.StringFlag{
+						Name:        "jira-url",
+						Description: "Jira base url.",
+					},
+					components.StringFlag{
+						Name:        "jira-username",
+						Description: "The Jira username, leave empty to use the jira-token as personal access token.",
+					},
+					components.StringFlag{
+						Name:        "jira-token",
+						Description: "The Jira API token or personal access token.",
+					},
+					components.StringFlag{
+						Name:        "version",
+						Description: "The name of the version, defaults to the build number.",
+					},
+					components.StringFlag{
+						Name:        "jira-projects",
+						Description: "Comma separated list of Jira project keys to manage the version in, defaults to all projects.",
+					},
+					components.StringFlag{
+						Name:        "environment",
+						Description: "The environment that the deployment targeted, uses the issues of the build instead if not set.",
+					},
+					components.BoolFlag{
+						Name:         "release",
+						Description:  "Enable to mark the version released once the deployment to a production environment is successful.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "dry-run",
+						Description:  "Enable to only log what would be send to Jira.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "include-pre-post-runs",
+						Description:  "Enable to include pipeline preRun and postRun steps.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "fail-on-reject",
+						Description:  "Enable to error out if any issues failed to update.",
+						DefaultValue: false,
+					},
+				},
+				Arguments: []components.Argument{
+					{
+						Name:        "build name",
+						Description: "The name of the build.",
+					},
+					{
+						Name:        "build number",
+						Description: "The number of the build.",
+					},
+				},
+				Action: func(c *components.Context) error {
+					return fixVersionCmd(c)
+				},
+			},
+			{
+				Name:        "release-notes",
+				Description: "Generate release notes from the issues and commits of a range of builds",
+				Aliases:     []string{"rn"},
+				Flags: []components.Flag{
+					components.StringFlag{
+						Name:        "server-id",
+						Description: "Server ID configured using the config command.",
+					},
+					components.StringFlag{
+						Name:        "project",
+						Description: "Artifactory project key.",
+					},
+					components.StringFlag{
+						Name:        "from-build",
+						Description: "The build number, exclusive, to start the release notes from, defaults to the previous build number.",
+					},
+					components.StringFlag{
+						Name:        "branch",
+						Description: "Only include builds of the branch.",
+					},
+					components.StringFlag{
+						Name:         "format",
+						Description:  "The format of the release notes, markdown, html or json.",
+						DefaultValue: commands.ReleaseNotesMarkdown,
+					},
+					components.StringFlag{
+						Name:        "template",
+						Description: "Path to a Go template to render the release notes with.",
+					},
+					components.StringFlag{
+						Name:        "group-by",
+						Description: "The issue field, recorded using the issue-fields flag of collect-issues, to group the issues by.",
+					},
+					components.StringFlag{
+						Name:        "output",
+						Description: "The file to write the release notes to, defaults to stdout.",
+					},
+				},
+				Arguments: []components.Argument{
+					{
+						Name:        "build name",
+						Description: "The name of the build.",
+					},
+					{
+						Name:        "build number",
+						Description: "The number of the build, inclusive, to end the release notes with.",
+					},
+				},
+				Action: func(c *components.Context) error {
+					return releaseNotesCmd(c)
+				},
+			},
+			{
+				Name:        "diff",
+				Description: "Compare the build-info of a build to the build-info of another build",
+				Aliases:     []string{"df"},
+				Flags: []components.Flag{
+					components.StringFlag{
+						Name:        "server-id",
+						Description: "Server ID configured using the config command.",
+					},
+					components.StringFlag{
+						Name:        "project",
+						Description: "Artifactory project key.",
+					},
+					components.StringFlag{
+						Name:        "compare-to",
+						Description: "The build number of the build to compare to, defaults to the previous build.",
+					},
+					components.StringFlag{
+						Name:         "format",
+						Description:  "The format of the diff, text or json.",
+						DefaultValue: commands.DiffText,
+					},
+					components.StringFlag{
+						Name:        "output",
+						Description: "The file to write the diff to, defaults to stdout.",
+					},
+				},
+				Arguments: []components.Argument{
+					{
+						Name:        "build name",
+						Description: "The name of the build.",
+					},
+					{
+						Name:        "build number",
+						Description: "The number of the build.",
+					},
+				},
+				Action: func(c *components.Context) error {
+					return diffCmd(c)
+				},
+			},
+			{
+				Name:        "backfill",
+				Description: "Send the build-infos of previously published builds to Jira",
+				Aliases:     []string{"bf"},
+				Flags: []components.Flag{
+					components.StringFlag{
+						Name:        "server-id",
+						Description: "Server ID configured using the config command.",
+					},
+					components.StringFlag{
+						Name:        "project",
+						Description: "Artifactory project key.",
+					},
+					components.StringFlag{
+						Name:        "jira-id",
+						Description: "Jira integration name.",
+					},
+					components.StringFlag{
+						Name:        "jira-url",
+						Description: "Jira base url.",
+					},
+					components.StringFlag{
+						Name:        "jira-client-id",
+						Description: "The OAuth clientId generated by Jira.",
+					},
+					components.StringFlag{
+						Name:        "jira-secret",
+						Description: "The OAuth secret generated by Jira.",
+					},
+					components.StringFlag{
+						Name:        "from-build",
+						Description: "The build number of the first build to send, defaults to the first published build.",
+					},
+					components.StringFlag{
+						Name:        "batch-size",
+						Description: "The number of builds to send per request, at most 100.",
+					},
+					components.StringFlag{
+						Name:        "interval",
+						Description: "The minimum duration between two requests to Jira, like 500ms or 2s.",
+					},
+					components.StringFlag{
+						Name:        "resume-file",
+						Description: "The file to record the progress in, a backfill resumes after the last build recorded in the file.",
+					},
+					components.StringFlag{
+						Name:        "output",
+						Description: "The file to write the report of the backfill to, or - to write it to stdout.",
+					},
+					components.BoolFlag{
+						Name:         "dry-run",
+						Description:  "Enable to only log what would be send to Jira.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "include-pre-post-runs",
+						Description:  "Enable to include pipeline preRun and postRun steps.",
+						DefaultValue: false,
+					},
+					components.BoolFlag{
+						Name:         "fail-on-reject",
+						Description:  "Enable to error out if any builds are rejected by Jira.",
+						DefaultValue: false,
+					},
+				},
+				Arguments: []components.Argument{
+					{
+						Name:        "build name",
+						Description: "The name of the build.",
+					},
+					{
+						Name:        "build number",
+						Description: "[Optional] The number of the last build to send, defaults to the latest build.",
+					},
+				},
+				Action: func(c *components.Context) error {
+					return backfillCmd(c)
+				},
+			},
+			{
+				Name:        "gate-deployment",
+				Description: "Send a pending deployment to Jira and wait until Jira allows the deployment",
+				Aliases:     []string{"gd"},
+				Flags: []components.Flag{
+					components.StringFlag{
+						Name:        "server-id",
+						Description: "Server ID configured using the config command.",
+					},
+					components.StringFlag{
+						Name:        "project",
+						Description: "Artifactory project key.",
+					},
+					components.StringFlag{
+						Name:        "jira-id",
+						Description: "Jira integration name.",
+					},
+					components.StringFlag{
+						Name:        "jira-url",
+						Description: "Jira base url.",
+					},
+					components.StringFlag{
+						Name:        "jira-client-id",
+						Description: "The OAuth clientId generated by Jira.",
+					},
+					components.StringFlag{
+						Name:        "jira-secret",
+						Description: "The OAuth secret generated by Jira.",
+					},
+					components.StringFlag{
+						Name:        "environment",
+						Description: "The environment that the deployment targets.",
+					},
+					components.StringFlag{
+						Name:        "timeout",
+						Description: "The maximum duration to wait for Jira to allow or prevent the deployment, like 30m or 2h.",
+					},
+					components.StringFlag{
+						Name:        "interval",
+						Description: "The duration between two checks of the gating status, like 30s or 1m.",
+					},
+					components.BoolFlag{
+						Name:         "dry-run",
+						Description:  "Enable to only log what would be send to Jira.",
+						DefaultValue: false,
+					},
+				},
+				Arguments: []components.Argument{
+					{
+						Name:        "build name",
+						Description: "The name of the build.",
+					},
+					{
+						Name:        "build number",
+						Description: "The number of the build.",
+					},
+				},
+				Action: func(c *components.Context) error {
+					return gateDeploymentCmd(c)
+				},
+			},
 			{
 				Name:        "notify-slack",
 				Description: "Send build-info to Slack",
@@ -340,9 +813,6 @@ func main() {
 
 func collectIssuesCmd(c *components.Context) error {
 	nargs := len(c.Arguments)
-	if nargs > 3 {
-		return errors.New(fmt.Sprintf("Wrong number of arguments (%d).", nargs))
-	}
 	buildConfiguration := CreateBuildConfiguration(c)
 	if err := buildConfiguration.ValidateBuildParams(); err != nil {
 		return err
@@ -356,11 +826,12 @@ func collectIssuesCmd(c *components.Context) error {
 		return err
 	}
 
-	collectIssueCommand := commands.NewCollectIssueCommand().SetBuildConfiguration(buildConfiguration).SetIssuesConfig(issueConfiguration)
-	if nargs == 3 {
-		collectIssueCommand.SetDotGitPath(c.Arguments[2])
+	collectIssueCommand := commands.NewCollectIssueCommand().SetBuildConfiguration(buildConfiguration).SetIssuesConfig(
+		issueConfiguration).SetIncludeSubmodules(c.GetBoolFlagValue("include-submodules")).SetOutput(c.GetStringFlagValue("output"))
+	if nargs >= 3 {
+		collectIssueCommand.SetDotGitPaths(c.Arguments[2:]...)
 	} else if nargs == 1 {
-		collectIssueCommand.SetDotGitPath(c.Arguments[0])
+		collectIssueCommand.SetDotGitPaths(c.Arguments[0])
 	}
 	return collectIssueCommand.Run()
 }
@@ -395,6 +866,13 @@ func sendBuildInfoCmd(c *components.Context) error {
 	}
 
 	sendBuildInfoCommand := commands.NewSendBuildInfoCommand().SetBuildConfiguration(buildConfiguration).SetJiraConfiguration(jiraConfiguration)
+	if value := c.GetStringFlagValue("state"); value != "" {
+		state, err := common.ParseState(value)
+		if err != nil {
+			return err
+		}
+		sendBuildInfoCommand.SetState(state)
+	}
 	return sendBuildInfoCommand.Run()
 }
 
@@ -422,6 +900,165 @@ func sendDeploymentInfoCmd(c *components.Context) error {
 	return sendDeploymentInfoCommand.Run()
 }
 
+func transitionIssuesCmd(c *components.Context) error {
+	nargs := len(c.Arguments)
+	if nargs > 2 {
+		return errors.New(fmt.Sprintf("Wrong number of arguments (%d).", nargs))
+	}
+	buildConfiguration := CreateBuildConfiguration(c)
+	if err := buildConfiguration.ValidateBuildParams(); err != nil {
+		return err
+	}
+
+	jiraConfiguration := CreateJiraTokenConfiguration(c)
+	if err := jiraConfiguration.ValidateJiraConfiguration(); err != nil {
+		return err
+	}
+
+	transitionIssuesCommand := commands.NewTransitionIssuesCommand().SetBuildConfiguration(buildConfiguration).SetJiraConfiguration(
+		jiraConfiguration).SetTransitions(util.SplitKeyValues(c.GetStringFlagValue("transitions"))).SetComment(
+		c.GetBoolFlagValue("comment")).SetOnlyFixed(c.GetBoolFlagValue("only-fixed"))
+	if c.GetStringFlagValue("environment") != "" {
+		deploymentInfo, err := CreateDeploymentInfo(c)
+		if err != nil {
+			return err
+		}
+		transitionIssuesCommand.SetDeploymentInfo(deploymentInfo)
+	}
+	return transitionIssuesCommand.Run()
+}
+
+func fixVersionCmd(c *components.Context) error {
+	nargs := len(c.Arguments)
+	if nargs > 2 {
+		return errors.New(fmt.Sprintf("Wrong number of arguments (%d).", nargs))
+	}
+	buildConfiguration := CreateBuildConfiguration(c)
+	if err := buildConfiguration.ValidateBuildParams(); err != nil {
+		return err
+	}
+
+	jiraConfiguration := CreateJiraTokenConfiguration(c)
+	if err := jiraConfiguration.ValidateJiraConfiguration(); err != nil {
+		return err
+	}
+
+	fixVersionCommand := commands.NewFixVersionCommand().SetBuildConfiguration(buildConfiguration).SetJiraConfiguration(
+		jiraConfiguration).SetVersion(c.GetStringFlagValue("version")).SetProjects(util.SplitList(c.GetStringFlagValue(
+		"jira-projects"))).SetRelease(c.GetBoolFlagValue("release"))
+	if c.GetStringFlagValue("environment") != "" {
+		deploymentInfo, err := CreateDeploymentInfo(c)
+		if err != nil {
+			return err
+		}
+		fixVersionCommand.SetDeploymentInfo(deploymentInfo)
+	}
+	return fixVersionCommand.Run()
+}
+
+func releaseNotesCmd(c *components.Context) error {
+	nargs := len(c.Arguments)
+	if nargs > 2 {
+		return errors.New(fmt.Sprintf("Wrong number of arguments (%d).", nargs))
+	}
+	buildConfiguration := CreateBuildConfiguration(c)
+	if err := buildConfiguration.ValidateBuildParams(); err != nil {
+		return err
+	}
+
+	releaseNotesConfiguration := CreateReleaseNotesConfiguration(c)
+	if err := releaseNotesConfiguration.ValidateReleaseNotesConfiguration(); err != nil {
+		return err
+	}
+
+	releaseNotesCommand := commands.NewReleaseNotesCommand().SetBuildConfiguration(buildConfiguration).SetReleaseNotesConfiguration(
+		releaseNotesConfiguration)
+	return releaseNotesCommand.Run()
+}
+
+func diffCmd(c *components.Context) error {
+	nargs := len(c.Arguments)
+	if nargs > 2 {
+		return errors.New(fmt.Sprintf("Wrong number of arguments (%d).", nargs))
+	}
+	buildConfiguration := CreateBuildConfiguration(c)
+	if err := buildConfiguration.ValidateBuildParams(); err != nil {
+		return err
+	}
+
+	diffConfiguration := CreateDiffConfiguration(c)
+	if err := diffConfiguration.ValidateDiffConfiguration(); err != nil {
+		return err
+	}
+
+	diffCommand := commands.NewDiffCommand().SetBuildConfiguration(buildConfiguration).SetDiffConfiguration(diffConfiguration)
+	return diffCommand.Run()
+}
+
+func backfillCmd(c *components.Context) error {
+	nargs := len(c.Arguments)
+	if nargs > 2 {
+		return errors.New(fmt.Sprintf("Wrong number of arguments (%d).", nargs))
+	}
+	// The build number is optional, it is the number of the last build to send.
+	buildConfiguration := CreateBuildConfiguration(c)
+	if nargs == 1 {
+		buildConfiguration.SetBuildName(c.Arguments[0])
+	} else if err := buildConfiguration.ValidateBuildParams(); err != nil {
+		return err
+	}
+
+	jiraConfiguration := CreateJiraConfiguration(c)
+	if err := jiraConfiguration.ValidateJiraConfiguration(); err != nil {
+		return err
+	}
+
+	backfillConfiguration, err := CreateBackfillConfiguration(c)
+	if err != nil {
+		return err
+	}
+	if err = backfillConfiguration.ValidateBackfillConfiguration(); err != nil {
+		return err
+	}
+
+	backfillCommand := commands.NewBackfillCommand().SetBuildConfiguration(buildConfiguration).SetJiraConfiguration(jiraConfiguration).
+		SetBackfillConfiguration(backfillConfiguration)
+	return backfillCommand.Run()
+}
+
+func gateDeploymentCmd(c *components.Context) error {
+	nargs := len(c.Arguments)
+	if nargs > 2 {
+		return errors.New(fmt.Sprintf("Wrong number of arguments (%d).", nargs))
+	}
+	buildConfiguration := CreateBuildConfiguration(c)
+	if err := buildConfiguration.ValidateBuildParams(); err != nil {
+		return err
+	}
+
+	jiraConfiguration := CreateJiraConfiguration(c)
+	if err := jiraConfiguration.ValidateJiraConfiguration(); err != nil {
+		return err
+	}
+
+	deploymentInfo, err := CreateDeploymentInfo(c)
+	if err != nil {
+		return err
+	}
+
+	gatingConfiguration, err := CreateGatingConfiguration(c)
+	if err != nil {
+		return err
+	}
+	if err = gatingConfiguration.ValidateGatingConfiguration(); err != nil {
+		return err
+	}
+
+	gateDeploymentCommand := commands.NewGateDeploymentCommand().SetBuildConfiguration(buildConfiguration).SetJiraConfiguration(
+		jiraConfiguration).SetDeploymentInfo(deploymentInfo).SetGatingConfiguration(gatingConfiguration)
+	return gateDeploymentCommand.Run()
+}
+
 func notifySlackCmd(c *components.Context) error {
 	nargs := len(c.Arguments)
 	if nargs > 2 {
@@ -483,6 +1120,8 @@ func CreateIssueConfiguration(c *components.Context) (*commands.IssuesConfigurat
 		issueConfiguration.SetTrackerDetails(url, c.GetStringFlagValue("tracker-username"), c.GetStringFlagValue("tracker-token"))
 	}
 	issueConfiguration.SetRegexp(c.GetStringFlagValue("regexp"))
+	issueConfiguration.SetBranchRegexp(c.GetStringFlagValue("branch-regexp"))
+	issueConfiguration.SetMessageRegexp(c.GetStringFlagValue("message-regexp"))
 	if index := c.GetStringFlagValue("key-group-index"); index != "" {
 		groupIndex, err := strconv.Atoi(index)
 		if err != nil {
@@ -492,6 +1131,22 @@ func CreateIssueConfiguration(c *components.Context) (*commands.IssuesConfigurat
 	}
 	issueConfiguration.SetAggregate(c.GetBoolFlagValue("aggregate"))
 	issueConfiguration.SetAggregationStatus(c.GetStringFlagValue("aggregation-status"))
+	if maxPreviousBuilds := c.GetStringFlagValue("max-previous-builds"); maxPreviousBuilds != "" {
+		maxBuilds, err := strconv.Atoi(maxPreviousBuilds)
+		if err != nil {
+			return nil, err
+		}
+		issueConfiguration.SetMaxPreviousBuilds(maxBuilds)
+	}
+	issueConfiguration.SetBaseline(c.GetStringFlagValue("baseline"))
+	issueConfiguration.SetBaselineStatus(c.GetStringFlagValue("baseline-status"))
+	issueConfiguration.SetSinceBuild(c.GetStringFlagValue("since-build"))
+	issueConfiguration.SetIncludePaths(util.SplitList(c.GetStringFlagValue("include-paths")))
+	issueConfiguration.SetExcludePaths(util.SplitList(c.GetStringFlagValue("exclude-paths")))
+	issueConfiguration.SetAllowProjects(util.SplitList(c.GetStringFlagValue("allow-projects")))
+	issueConfiguration.SetDenyProjects(util.SplitList(c.GetStringFlagValue("deny-projects")))
+	issueConfiguration.SetDiscoverProjects(c.GetBoolFlagValue("discover-projects"))
+	issueConfiguration.SetFields(util.SplitList(c.GetStringFlagValue("issue-fields")))
 	return issueConfiguration, nil
 }
 
@@ -505,6 +1160,18 @@ func CreateJiraConfiguration(c *components.Context) *commands.JiraConfiguration
 	jiraConfiguration.SetDryRun(c.GetBoolFlagValue("dry-run"))
 	jiraConfiguration.SetIncludePrePostRunSteps(c.GetBoolFlagValue("include-pre-post-runs"))
 	jiraConfiguration.SetFailOnReject(c.GetBoolFlagValue("fail-on-reject"))
+	jiraConfiguration.SetRemoteLinks(c.GetBoolFlagValue("remote-links"))
+	return jiraConfiguration
+}
+
+// CreateJiraTokenConfiguration creates the Jira configuration for commands that use the Jira REST API, using a username and API
+// token, or a personal access token.
+func CreateJiraTokenConfiguration(c *components.Context) *commands.JiraConfiguration {
+	jiraConfiguration := CreateJiraConfiguration(c)
+	if url := c.GetStringFlagValue("jira-url"); url != "" {
+		jiraConfiguration.SetJiraDetails(url, c.GetStringFlagValue("jira-username"), c.GetStringFlagValue("jira-token"))
+	}
+	jiraConfiguration.SetTokenAuth(true)
 	return jiraConfiguration
 }
 
@@ -516,7 +1183,69 @@ func CreateDeploymentInfo(c *components.Context) (*commands.DeploymentInfo, erro
 	if environment == "" {
 		return nil, errorutils.CheckErrorf("Missing deployment environment")
 	}
-	return commands.NewDeploymentInfo(environment), nil
+	return commands.NewDeploymentInfo(environment)
+}
+
+func CreateReleaseNotesConfiguration(c *components.Context) *commands.ReleaseNotesConfiguration {
+	releaseNotesConfiguration := new(commands.ReleaseNotesConfiguration)
+	releaseNotesConfiguration.SetServerID(c.GetStringFlagValue("server-id"))
+	releaseNotesConfiguration.SetFromBuild(c.GetStringFlagValue("from-build"))
+	releaseNotesConfiguration.SetBranch(c.GetStringFlagValue("branch"))
+	releaseNotesConfiguration.SetFormat(c.GetStringFlagValue("format"))
+	releaseNotesConfiguration.SetTemplateFile(c.GetStringFlagValue("template"))
+	releaseNotesConfiguration.SetGroupBy(c.GetStringFlagValue("group-by"))
+	releaseNotesConfiguration.SetOutput(c.GetStringFlagValue("output"))
+	return releaseNotesConfiguration
+}
+
+func CreateDiffConfiguration(c *components.Context) *commands.DiffConfiguration {
+	diffConfiguration := new(commands.DiffConfiguration)
+	diffConfiguration.SetServerID(c.GetStringFlagValue("server-id"))
+	diffConfiguration.SetCompareTo(c.GetStringFlagValue("compare-to"))
+	diffConfiguration.SetFormat(c.GetStringFlagValue("format"))
+	diffConfiguration.SetOutput(c.GetStringFlagValue("output"))
+	return diffConfiguration
+}
+
+func CreateBackfillConfiguration(c *components.Context) (*commands.BackfillConfiguration, error) {
+	backfillConfiguration := new(commands.BackfillConfiguration)
+	backfillConfiguration.SetFromBuild(c.GetStringFlagValue("from-build"))
+	if size := c.GetStringFlagValue("batch-size"); size != "" {
+		batchSize, err := strconv.Atoi(size)
+		if err != nil {
+			return nil, err
+		}
+		backfillConfiguration.SetBatchSize(batchSize)
+	}
+	if value := c.GetStringFlagValue("interval"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		backfillConfiguration.SetInterval(interval)
+	}
+	backfillConfiguration.SetResumeFile(c.GetStringFlagValue("resume-file"))
+	backfillConfiguration.SetOutput(c.GetStringFlagValue("output"))
+	return backfillConfiguration, nil
+}
+
+func CreateGatingConfiguration(c *components.Context) (*commands.GatingConfiguration, error) {
+	gatingConfiguration := new(commands.GatingConfiguration)
+	if value := c.GetStringFlagValue("timeout"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		gatingConfiguration.SetTimeout(timeout)
+	}
+	if value := c.GetStringFlagValue("interval"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		gatingConfiguration.SetInterval(interval)
+	}
+	return gatingConfiguration, nil
 }
 
 func CreateSlackConfiguration(c *components.Context) *commands.SlackConfiguration {
@@ -19,7 +19,7 @@ type jiraDetails struct {
 
 func (js *jiraDetails) GetVersion() (string, error) {
 	info := &jira.ServerInfo{}
-	if err := js.GetRequest("rest/api/3/serverInfo", &info); err != nil {
+	if err := js.GetRequest("rest/api/2/serverInfo", &info); err != nil {
 		return "", err
 	}
 	return info.Version, nil
@@ -49,3 +49,27 @@ type bitbucketDetails struct {
 func (bs *bitbucketDetails) GetVersion() (string, error) {
 	return "Cloud", nil
 }
+
+func NewGitHubDetails() auth.ServiceDetails {
+	return &gitHubDetails{}
+}
+
+type gitHubDetails struct {
+	auth.CommonConfigFields
+}
+
+func (gs *gitHubDetails) GetVersion() (string, error) {
+	return "Cloud", nil
+}
+
+func NewGitLabDetails() auth.ServiceDetails {
+	return &gitLabDetails{}
+}
+
+type gitLabDetails struct {
+	auth.CommonConfigFields
+}
+
+func (gs *gitLabDetails) GetVersion() (string, error) {
+	return "v4", nil
+}
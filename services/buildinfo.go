@@ -13,11 +13,13 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/marvelution/ext-build-info/services/common"
+	"math"
 	"net/http"
 	"path"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 func CreateExtBuildInfoService(serverDetails *utilsconfig.ServerDetails) (*ExtBuildInfoService, error) {
@@ -61,9 +63,8 @@ func (bis *ExtBuildInfoService) GetBuildInfosInRange(buildConfig *artutils.Build
 	}
 	buildInfoService := bis.getBuildInfoService()
 	buildInfos := &[]buildinfo.BuildInfo{}
-	var nonNumericRegex = regexp.MustCompile(`[^0-9]+`)
 	for _, build := range buildRuns.BuildsNumbers {
-		buildNumber, err := strconv.ParseInt(nonNumericRegex.ReplaceAllString(build.Uri, ""), 10, 64)
+		buildNumber, err := build.GetNumber()
 		if err != nil {
 			log.Debug("Excluding build "+build.Uri+"as it cannot be parsed to a build number", err)
 		} else if buildNumber > startExclusive && buildNumber <= endInclusive {
@@ -77,16 +78,11 @@ func (bis *ExtBuildInfoService) GetBuildInfosInRange(buildConfig *artutils.Build
 				log.Warn("Excluding build-info "+buildInfoParams.BuildName+" #"+buildInfoParams.BuildNumber+" because of error:", err)
 			} else if !found {
 				log.Debug("Excluding build-info " + buildInfoParams.BuildName + " #" + buildInfoParams.BuildNumber + " it was not found")
+			} else if branch == "" || hasBranch(&publishedBuildInfo.BuildInfo, branch) {
+				log.Info("Including build-info " + buildInfoParams.BuildName + " #" + buildInfoParams.BuildNumber)
+				*buildInfos = append(*buildInfos, publishedBuildInfo.BuildInfo)
 			} else {
-				for _, vcs := range publishedBuildInfo.BuildInfo.VcsList {
-					if vcs.Branch == branch {
-						log.Info("Including build-info " + buildInfoParams.BuildName + " #" + buildInfoParams.BuildNumber)
-						*buildInfos = append(*buildInfos, publishedBuildInfo.BuildInfo)
-						break
-					} else {
-						log.Debug("Excluding build-info " + buildInfoParams.BuildName + " #" + buildInfoParams.BuildNumber + " branch doesn't match")
-					}
-				}
+				log.Debug("Excluding build-info " + buildInfoParams.BuildName + " #" + buildInfoParams.BuildNumber + " branch doesn't match")
 			}
 		}
 	}
@@ -94,6 +90,97 @@ func (bis *ExtBuildInfoService) GetBuildInfosInRange(buildConfig *artutils.Build
 	return buildInfos, nil
 }
 
+// Returns true if any of the vcs entries of the build-info is of the branch.
+func hasBranch(buildInfo *buildinfo.BuildInfo, branch string) bool {
+	for _, vcs := range buildInfo.VcsList {
+		if vcs.Branch == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkBuildInfos walks the published build-infos of the build that are older than the build number of the build configuration,
+// starting with the newest build. Walking stops as soon as the walkFunc returns false.
+func (bis *ExtBuildInfoService) WalkBuildInfos(buildConfig *artutils.BuildConfiguration, walkFunc func(buildInfo *ExtBuildInfo) (bool, error)) error {
+	buildName, err := buildConfig.GetBuildName()
+	if err != nil {
+		return err
+	}
+	buildRuns, err := bis.GetBuildRuns(buildName, buildConfig.GetProject())
+	if err != nil || buildRuns == nil {
+		return err
+	}
+	currentBuildNumber := int64(math.MaxInt64)
+	if number, err := buildConfig.GetBuildNumber(); err == nil && number != "" {
+		if parsedNumber, err := strconv.ParseInt(number, 10, 64); err == nil {
+			currentBuildNumber = parsedNumber
+		}
+	}
+
+	var buildNumbers []int64
+	for _, build := range buildRuns.BuildsNumbers {
+		buildNumber, err := build.GetNumber()
+		if err != nil {
+			log.Debug("Excluding build "+build.Uri+" as it cannot be parsed to a build number", err)
+		} else if buildNumber < currentBuildNumber {
+			buildNumbers = append(buildNumbers, buildNumber)
+		}
+	}
+	sort.Slice(buildNumbers, func(i, j int) bool { return buildNumbers[i] > buildNumbers[j] })
+
+	for _, buildNumber := range buildNumbers {
+		buildInfo, err := bis.GetBuildInfo(buildName, strconv.FormatInt(buildNumber, 10), buildConfig.GetProject())
+		if err != nil {
+			return err
+		}
+		if buildInfo == nil {
+			log.Debug("Skipping build-info " + buildName + " #" + strconv.FormatInt(buildNumber, 10) + " it was not found")
+			continue
+		}
+		if proceed, err := walkFunc(buildInfo); err != nil || !proceed {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBuildInfo returns the published build-info including its promotion statuses, or nil if it was not found.
+func (bis *ExtBuildInfoService) GetBuildInfo(buildName, buildNumber, projectKey string) (*ExtBuildInfo, error) {
+	httpClientsDetails := bis.GetArtifactoryDetails().CreateHttpClientDetails()
+	restApi := path.Join("api/build/", buildName, buildNumber)
+
+	queryParams := make(map[string]string)
+	if projectKey != "" {
+		queryParams["project"] = projectKey
+	}
+
+	requestFullUrl, err := utils.BuildArtifactoryUrl(bis.GetArtifactoryDetails().GetUrl(), restApi, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := bis.GetJfrogHttpClient()
+	log.Debug("Getting build-info from: ", requestFullUrl)
+	resp, body, _, err := httpClient.SendGet(requestFullUrl, true, &httpClientsDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		log.Debug("Artifactory response: " + resp.Status + "\n" + clientutils.IndentJson(body))
+		return nil, nil
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	publishedBuildInfo := &ExtPublishedBuildInfo{}
+	if err := json.Unmarshal(body, publishedBuildInfo); err != nil {
+		return nil, err
+	}
+	return &publishedBuildInfo.BuildInfo, nil
+}
+
 func (bis *ExtBuildInfoService) GetBuildRuns(buildName, projectKey string) (*BuildRuns, error) {
 	httpClientsDetails := bis.GetArtifactoryDetails().CreateHttpClientDetails()
 	restApi := path.Join("api/build/", buildName)
@@ -144,3 +231,51 @@ type BuildNumber struct {
 	Uri string `json:"uri"`
 	//Started time.Time `json:"started"`
 }
+
+var nonNumericRegex = regexp.MustCompile(`[^0-9]+`)
+
+// GetNumber returns the numeric build number from the uri.
+func (bn *BuildNumber) GetNumber() (int64, error) {
+	return strconv.ParseInt(nonNumericRegex.ReplaceAllString(bn.Uri, ""), 10, 64)
+}
+
+type ExtPublishedBuildInfo struct {
+	Uri       string       `json:"uri,omitempty"`
+	BuildInfo ExtBuildInfo `json:"buildInfo,omitempty"`
+}
+
+// ExtBuildInfo extends the build-info with the promotion statuses of the build.
+type ExtBuildInfo struct {
+	buildinfo.BuildInfo
+	Statuses []BuildStatus `json:"statuses,omitempty"`
+}
+
+// HasStatus returns true if the build was ever promoted with the status.
+func (bi *ExtBuildInfo) HasStatus(status string) bool {
+	for _, buildStatus := range bi.Statuses {
+		if strings.EqualFold(buildStatus.Status, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetState returns the state of the latest promotion status that is named after a final state, like failed or cancelled, or
+// successful if the build was never promoted with such a status, since a published build completed.
+func (bi *ExtBuildInfo) GetState() common.State {
+	for i := len(bi.Statuses) - 1; i >= 0; i-- {
+		if state, err := common.ParseState(bi.Statuses[i].Status); err == nil && state.IsFinal() {
+			return state
+		}
+	}
+	return common.Successful
+}
+
+type BuildStatus struct {
+	Status     string `json:"status"`
+	Comment    string `json:"comment,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	User       string `json:"user,omitempty"`
+	CiUser     string `json:"ciUser,omitempty"`
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"github.com/marvelution/ext-build-info/services/common"
+	"testing"
+)
+
+func TestExtBuildInfoGetState(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		state    common.State
+	}{
+		{"no statuses", nil, common.Successful},
+		{"no state statuses", []string{"Released", "Staged"}, common.Successful},
+		{"state status", []string{"Released", "Failed"}, common.Failed},
+		{"latest state status", []string{"failed", "Released", "cancelled", "Staged"}, common.Cancelled},
+		{"non-final state statuses", []string{"in_progress", "unknown"}, common.Successful},
+		{"final before non-final state status", []string{"failed", "pending"}, common.Failed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buildInfo := &ExtBuildInfo{}
+			for _, status := range test.statuses {
+				buildInfo.Statuses = append(buildInfo.Statuses, BuildStatus{Status: status})
+			}
+			if state := buildInfo.GetState(); state != test.state {
+				t.Errorf("GetState() = %s, want %s", state, test.state)
+			}
+		})
+	}
+}
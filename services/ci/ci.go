@@ -0,0 +1,76 @@
+package ci
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"os"
+)
+
+// JFrogPipelines is the name of the JFrog Pipelines provider.
+const JFrogPipelines = "JFrog Pipelines"
+
+// Provider reads the details of the current run from the environment of a CI system.
+type Provider interface {
+	// Name returns the name of the CI system.
+	Name() string
+	// Detect returns true if the current process runs in the CI system.
+	Detect() bool
+	// Environment returns the details of the current run.
+	Environment() Environment
+}
+
+// Environment holds the details of the current run of a CI system, details that are not available are left empty.
+type Environment struct {
+	Provider     string
+	PipelineName string
+	RunId        string
+	RunNumber    string
+	RunUrl       string
+	Branch       string
+	Commit       string
+	PullRequest  string
+	// Branches holds the branch of each commit, for CI systems that can check out multiple repositories.
+	Branches map[string]string
+}
+
+// GetBranch returns the branch of the commit, or an empty string if the branch of the commit is unknown.
+func (e Environment) GetBranch(commit string) string {
+	if branch, found := e.Branches[commit]; found {
+		return branch
+	}
+	if commit != "" && commit == e.Commit {
+		return e.Branch
+	}
+	return ""
+}
+
+// IsPullRequest returns true if the run is for a pull request.
+func (e Environment) IsPullRequest() bool {
+	return e.PullRequest != ""
+}
+
+var providers = []Provider{
+	&jfrogPipelines{},
+	&githubActions{},
+	&gitlabCi{},
+	&jenkins{},
+	&bitbucketPipelines{},
+	&azurePipelines{},
+}
+
+// Detect returns the environment of the CI system the process runs in. If no CI system is detected, only the run url is set from
+// the build url of the JFrog CLI.
+func Detect() Environment {
+	for _, provider := range providers {
+		if provider.Detect() {
+			log.Debug("Detected CI system ", provider.Name())
+			environment := provider.Environment()
+			environment.Provider = provider.Name()
+			if environment.RunUrl == "" {
+				environment.RunUrl = os.Getenv("JFROG_CLI_BUILD_URL")
+			}
+			return environment
+		}
+	}
+	log.Debug("No CI system detected")
+	return Environment{RunUrl: os.Getenv("JFROG_CLI_BUILD_URL")}
+}
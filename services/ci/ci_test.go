@@ -0,0 +1,128 @@
+package ci
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The environment variables read by the providers, the variables of JFrog Pipelines resources are found by their commitSha suffix.
+var ciVariables = []string{
+	"JFROG_CLI_BUILD_URL", "pipeline_name", "run_id", "run_number", "step_url",
+	"GITHUB_ACTIONS", "GITHUB_WORKFLOW", "GITHUB_RUN_ID", "GITHUB_RUN_NUMBER", "GITHUB_SERVER_URL", "GITHUB_REPOSITORY", "GITHUB_SHA",
+	"GITHUB_HEAD_REF", "GITHUB_REF", "GITHUB_REF_TYPE", "GITHUB_REF_NAME",
+	"GITLAB_CI", "CI_PROJECT_PATH", "CI_PIPELINE_ID", "CI_PIPELINE_IID", "CI_PIPELINE_URL", "CI_COMMIT_SHA", "CI_COMMIT_BRANCH",
+	"CI_MERGE_REQUEST_IID", "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME",
+	"JENKINS_URL", "JOB_NAME", "BUILD_ID", "BUILD_NUMBER", "BUILD_URL", "GIT_COMMIT", "CHANGE_ID", "CHANGE_BRANCH", "BRANCH_NAME",
+	"GIT_LOCAL_BRANCH", "GIT_BRANCH",
+	"BITBUCKET_BUILD_NUMBER", "BITBUCKET_COMMIT", "BITBUCKET_REPO_FULL_NAME", "BITBUCKET_PIPELINE_UUID", "BITBUCKET_GIT_HTTP_ORIGIN",
+	"BITBUCKET_BRANCH", "BITBUCKET_PR_ID",
+	"TF_BUILD", "BUILD_DEFINITIONNAME", "BUILD_BUILDID", "SYSTEM_COLLECTIONURI", "SYSTEM_TEAMPROJECT", "BUILD_SOURCEVERSION",
+	"BUILD_SOURCEBRANCH", "SYSTEM_PULLREQUEST_SOURCEBRANCH", "SYSTEM_PULLREQUEST_PULLREQUESTNUMBER", "SYSTEM_PULLREQUEST_PULLREQUESTID",
+}
+
+// Replaces the CI environment variables of the process by the given variables for the duration of the test.
+func setCiEnvironment(t *testing.T, variables map[string]string) {
+	for _, name := range ciVariables {
+		t.Setenv(name, "")
+	}
+	for _, variable := range os.Environ() {
+		if name, _, _ := strings.Cut(variable, "="); strings.HasSuffix(name, "commitSha") {
+			t.Setenv(name, "")
+		}
+	}
+	for name, value := range variables {
+		t.Setenv(name, value)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name        string
+		variables   map[string]string
+		environment Environment
+	}{
+		{"no CI system", map[string]string{"JFROG_CLI_BUILD_URL": "https://ci.example.com/1"},
+			Environment{RunUrl: "https://ci.example.com/1"}},
+		{"JFrog Pipelines", map[string]string{"pipeline_name": "app", "run_id": "12", "run_number": "3",
+			"step_url": "https://pipelines.example.com/12", "res_lib_commitSha": "def", "res_lib_branchName": "develop",
+			"res_app_commitSha": "abc", "res_app_branchName": "main", "res_app_isPullRequest": "true", "res_app_pullRequestNumber": "4"},
+			Environment{Provider: "JFrog Pipelines", PipelineName: "app", RunId: "12", RunNumber: "3", RunUrl: "https://pipelines.example.com/12",
+				Commit: "abc", Branch: "main", PullRequest: "4", Branches: map[string]string{"abc": "main", "def": "develop"}}},
+		{"JFrog Pipelines takes precedence", map[string]string{"pipeline_name": "app", "run_id": "12", "GITHUB_ACTIONS": "true",
+			"JFROG_CLI_BUILD_URL": "https://ci.example.com/1"},
+			Environment{Provider: "JFrog Pipelines", PipelineName: "app", RunId: "12", RunUrl: "https://ci.example.com/1",
+				Branches: map[string]string{}}},
+		{"GitHub Actions", map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_WORKFLOW": "build", "GITHUB_RUN_ID": "100",
+			"GITHUB_RUN_NUMBER": "7", "GITHUB_SERVER_URL": "https://github.com", "GITHUB_REPOSITORY": "owner/repo", "GITHUB_SHA": "abc",
+			"GITHUB_REF": "refs/heads/main", "GITHUB_REF_TYPE": "branch", "GITHUB_REF_NAME": "main"},
+			Environment{Provider: "GitHub Actions", PipelineName: "build", RunId: "100", RunNumber: "7",
+				RunUrl: "https://github.com/owner/repo/actions/runs/100", Commit: "abc", Branch: "main"}},
+		{"GitHub Actions pull request", map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_RUN_ID": "100",
+			"GITHUB_SERVER_URL": "https://github.com", "GITHUB_REPOSITORY": "owner/repo", "GITHUB_SHA": "abc",
+			"GITHUB_REF": "refs/pull/42/merge", "GITHUB_HEAD_REF": "feature", "GITHUB_REF_NAME": "42/merge"},
+			Environment{Provider: "GitHub Actions", RunId: "100", RunUrl: "https://github.com/owner/repo/actions/runs/100", Commit: "abc",
+				Branch: "feature", PullRequest: "42"}},
+		{"GitHub Actions tag", map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_RUN_ID": "100", "GITHUB_SERVER_URL": "https://github.com",
+			"GITHUB_REPOSITORY": "owner/repo", "GITHUB_SHA": "abc", "GITHUB_REF": "refs/tags/v1",
+			"GITHUB_REF_TYPE": "tag", "GITHUB_REF_NAME": "v1"},
+			Environment{Provider: "GitHub Actions", RunId: "100", RunUrl: "https://github.com/owner/repo/actions/runs/100", Commit: "abc"}},
+		{"GitLab CI merge request", map[string]string{"GITLAB_CI": "true", "CI_PROJECT_PATH": "group/project", "CI_PIPELINE_ID": "200",
+			"CI_PIPELINE_IID": "8", "CI_COMMIT_SHA": "abc", "CI_MERGE_REQUEST_IID": "5", "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature",
+			"JFROG_CLI_BUILD_URL": "https://ci.example.com/1"},
+			Environment{Provider: "GitLab CI", PipelineName: "group/project", RunId: "200", RunNumber: "8", RunUrl: "https://ci.example.com/1",
+				Commit: "abc", Branch: "feature", PullRequest: "5"}},
+		{"Jenkins", map[string]string{"JENKINS_URL": "https://jenkins.example.com/", "JOB_NAME": "app", "BUILD_ID": "9",
+			"BUILD_NUMBER": "9", "BUILD_URL": "https://jenkins.example.com/job/app/9/", "GIT_COMMIT": "abc", "GIT_BRANCH": "origin/main"},
+			Environment{Provider: "Jenkins", PipelineName: "app", RunId: "9", RunNumber: "9", RunUrl: "https://jenkins.example.com/job/app/9/",
+				Commit: "abc", Branch: "main"}},
+		{"Jenkins multibranch pull request", map[string]string{"JENKINS_URL": "https://jenkins.example.com/", "BUILD_NUMBER": "9",
+			"CHANGE_ID": "6", "CHANGE_BRANCH": "feature", "BRANCH_NAME": "PR-6", "GIT_BRANCH": "PR-6"},
+			Environment{Provider: "Jenkins", RunNumber: "9", Branch: "feature", PullRequest: "6"}},
+		{"Bitbucket Pipelines", map[string]string{"BITBUCKET_BUILD_NUMBER": "10", "BITBUCKET_COMMIT": "abc",
+			"BITBUCKET_REPO_FULL_NAME": "workspace/repo", "BITBUCKET_PIPELINE_UUID": "{uuid}",
+			"BITBUCKET_GIT_HTTP_ORIGIN": "https://bitbucket.org/workspace/repo", "BITBUCKET_BRANCH": "main"},
+			Environment{Provider: "Bitbucket Pipelines", PipelineName: "workspace/repo", RunId: "{uuid}", RunNumber: "10",
+				RunUrl: "https://bitbucket.org/workspace/repo/addon/pipelines/home#!/results/10", Commit: "abc", Branch: "main"}},
+		{"Azure Pipelines", map[string]string{"TF_BUILD": "True", "BUILD_DEFINITIONNAME": "app", "BUILD_BUILDID": "11",
+			"SYSTEM_COLLECTIONURI": "https://dev.azure.com/org/", "SYSTEM_TEAMPROJECT": "project", "BUILD_SOURCEVERSION": "abc",
+			"BUILD_SOURCEBRANCH": "refs/heads/main"},
+			Environment{Provider: "Azure Pipelines", PipelineName: "app", RunId: "11", RunNumber: "11",
+				RunUrl: "https://dev.azure.com/org/project/_build/results?buildId=11", Commit: "abc", Branch: "main"}},
+		{"Azure Pipelines pull request", map[string]string{"TF_BUILD": "True", "BUILD_BUILDID": "11", "SYSTEM_COLLECTIONURI": "https://dev.azure.com/org/",
+			"SYSTEM_TEAMPROJECT": "project", "BUILD_SOURCEVERSION": "abc",
+			"BUILD_SOURCEBRANCH": "refs/pull/3/merge", "SYSTEM_PULLREQUEST_SOURCEBRANCH": "refs/heads/feature",
+			"SYSTEM_PULLREQUEST_PULLREQUESTID": "3"},
+			Environment{Provider: "Azure Pipelines", RunId: "11", RunNumber: "11", RunUrl: "https://dev.azure.com/org/project/_build/results?buildId=11", Commit: "abc",
+				Branch: "feature", PullRequest: "3"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setCiEnvironment(t, test.variables)
+			if environment := Detect(); !reflect.DeepEqual(environment, test.environment) {
+				t.Errorf("Detect() = %+v, want %+v", environment, test.environment)
+			}
+		})
+	}
+}
+
+func TestEnvironmentGetBranch(t *testing.T) {
+	environment := Environment{Commit: "abc", Branch: "main", Branches: map[string]string{"def": "develop"}}
+	tests := []struct {
+		commit string
+		branch string
+	}{
+		{"abc", "main"},
+		{"def", "develop"},
+		{"ghi", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.commit, func(t *testing.T) {
+			if branch := environment.GetBranch(test.commit); branch != test.branch {
+				t.Errorf("GetBranch(%s) = %s, want %s", test.commit, branch, test.branch)
+			}
+		})
+	}
+}
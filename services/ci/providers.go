@@ -0,0 +1,191 @@
+package ci
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// JFrog Pipelines, the branch and commit are read from the git repository resources of the run.
+type jfrogPipelines struct{}
+
+func (p *jfrogPipelines) Name() string {
+	return JFrogPipelines
+}
+
+func (p *jfrogPipelines) Detect() bool {
+	return os.Getenv("run_id") != "" && os.Getenv("pipeline_name") != ""
+}
+
+func (p *jfrogPipelines) Environment() Environment {
+	environment := Environment{
+		PipelineName: os.Getenv("pipeline_name"),
+		RunId:        os.Getenv("run_id"),
+		RunNumber:    os.Getenv("run_number"),
+		RunUrl:       os.Getenv("JFROG_CLI_BUILD_URL"),
+		Branches:     map[string]string{},
+	}
+	if environment.RunUrl == "" {
+		environment.RunUrl = os.Getenv("step_url")
+	}
+	// Resources are sorted by name, so that the first resource is used for the commit and branch of the run.
+	var names []string
+	for _, variable := range os.Environ() {
+		name, value, _ := strings.Cut(variable, "=")
+		if strings.HasSuffix(name, "commitSha") && value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prefix := strings.TrimSuffix(name, "commitSha")
+		commit := os.Getenv(name)
+		branch := os.Getenv(prefix + "branchName")
+		environment.Branches[commit] = branch
+		if environment.Commit == "" {
+			environment.Commit = commit
+			environment.Branch = branch
+			if os.Getenv(prefix+"isPullRequest") == "true" {
+				environment.PullRequest = os.Getenv(prefix + "pullRequestNumber")
+			}
+		}
+	}
+	return environment
+}
+
+type githubActions struct{}
+
+func (p *githubActions) Name() string {
+	return "GitHub Actions"
+}
+
+func (p *githubActions) Detect() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+func (p *githubActions) Environment() Environment {
+	environment := Environment{
+		PipelineName: os.Getenv("GITHUB_WORKFLOW"),
+		RunId:        os.Getenv("GITHUB_RUN_ID"),
+		RunNumber:    os.Getenv("GITHUB_RUN_NUMBER"),
+		RunUrl:       os.Getenv("GITHUB_SERVER_URL") + "/" + os.Getenv("GITHUB_REPOSITORY") + "/actions/runs/" + os.Getenv("GITHUB_RUN_ID"),
+		Commit:       os.Getenv("GITHUB_SHA"),
+		Branch:       os.Getenv("GITHUB_HEAD_REF"),
+	}
+	if environment.Branch == "" && os.Getenv("GITHUB_REF_TYPE") == "branch" {
+		environment.Branch = os.Getenv("GITHUB_REF_NAME")
+	}
+	// Pull requests are checked out using refs/pull/<number>/merge
+	if ref := os.Getenv("GITHUB_REF"); strings.HasPrefix(ref, "refs/pull/") {
+		environment.PullRequest = strings.Split(strings.TrimPrefix(ref, "refs/pull/"), "/")[0]
+	}
+	return environment
+}
+
+type gitlabCi struct{}
+
+func (p *gitlabCi) Name() string {
+	return "GitLab CI"
+}
+
+func (p *gitlabCi) Detect() bool {
+	return os.Getenv("GITLAB_CI") == "true"
+}
+
+func (p *gitlabCi) Environment() Environment {
+	environment := Environment{
+		PipelineName: os.Getenv("CI_PROJECT_PATH"),
+		RunId:        os.Getenv("CI_PIPELINE_ID"),
+		RunNumber:    os.Getenv("CI_PIPELINE_IID"),
+		RunUrl:       os.Getenv("CI_PIPELINE_URL"),
+		Commit:       os.Getenv("CI_COMMIT_SHA"),
+		Branch:       os.Getenv("CI_COMMIT_BRANCH"),
+		PullRequest:  os.Getenv("CI_MERGE_REQUEST_IID"),
+	}
+	if environment.Branch == "" {
+		environment.Branch = os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
+	}
+	return environment
+}
+
+type jenkins struct{}
+
+func (p *jenkins) Name() string {
+	return "Jenkins"
+}
+
+func (p *jenkins) Detect() bool {
+	return os.Getenv("JENKINS_URL") != "" && os.Getenv("BUILD_NUMBER") != ""
+}
+
+func (p *jenkins) Environment() Environment {
+	environment := Environment{
+		PipelineName: os.Getenv("JOB_NAME"),
+		RunId:        os.Getenv("BUILD_ID"),
+		RunNumber:    os.Getenv("BUILD_NUMBER"),
+		RunUrl:       os.Getenv("BUILD_URL"),
+		Commit:       os.Getenv("GIT_COMMIT"),
+		PullRequest:  os.Getenv("CHANGE_ID"),
+	}
+	// Multibranch pipelines set the branch of the change, or of the job, other jobs only have the branch of the git plugin.
+	for _, name := range []string{"CHANGE_BRANCH", "BRANCH_NAME", "GIT_LOCAL_BRANCH", "GIT_BRANCH"} {
+		if branch := os.Getenv(name); branch != "" {
+			environment.Branch = strings.TrimPrefix(branch, "origin/")
+			break
+		}
+	}
+	return environment
+}
+
+type bitbucketPipelines struct{}
+
+func (p *bitbucketPipelines) Name() string {
+	return "Bitbucket Pipelines"
+}
+
+func (p *bitbucketPipelines) Detect() bool {
+	return os.Getenv("BITBUCKET_BUILD_NUMBER") != "" && os.Getenv("BITBUCKET_COMMIT") != ""
+}
+
+func (p *bitbucketPipelines) Environment() Environment {
+	return Environment{
+		PipelineName: os.Getenv("BITBUCKET_REPO_FULL_NAME"),
+		RunId:        os.Getenv("BITBUCKET_PIPELINE_UUID"),
+		RunNumber:    os.Getenv("BITBUCKET_BUILD_NUMBER"),
+		RunUrl:       os.Getenv("BITBUCKET_GIT_HTTP_ORIGIN") + "/addon/pipelines/home#!/results/" + os.Getenv("BITBUCKET_BUILD_NUMBER"),
+		Commit:       os.Getenv("BITBUCKET_COMMIT"),
+		Branch:       os.Getenv("BITBUCKET_BRANCH"),
+		PullRequest:  os.Getenv("BITBUCKET_PR_ID"),
+	}
+}
+
+// Azure Pipelines, the build id is used as run number since the build number is a formatted name, like 20240101.1
+type azurePipelines struct{}
+
+func (p *azurePipelines) Name() string {
+	return "Azure Pipelines"
+}
+
+func (p *azurePipelines) Detect() bool {
+	return strings.EqualFold(os.Getenv("TF_BUILD"), "true")
+}
+
+func (p *azurePipelines) Environment() Environment {
+	environment := Environment{
+		PipelineName: os.Getenv("BUILD_DEFINITIONNAME"),
+		RunId:        os.Getenv("BUILD_BUILDID"),
+		RunNumber:    os.Getenv("BUILD_BUILDID"),
+		RunUrl: strings.TrimSuffix(os.Getenv("SYSTEM_COLLECTIONURI"), "/") + "/" + os.Getenv("SYSTEM_TEAMPROJECT") +
+			"/_build/results?buildId=" + os.Getenv("BUILD_BUILDID"),
+		Commit:      os.Getenv("BUILD_SOURCEVERSION"),
+		Branch:      strings.TrimPrefix(os.Getenv("SYSTEM_PULLREQUEST_SOURCEBRANCH"), "refs/heads/"),
+		PullRequest: os.Getenv("SYSTEM_PULLREQUEST_PULLREQUESTNUMBER"),
+	}
+	if ref := os.Getenv("BUILD_SOURCEBRANCH"); environment.Branch == "" && strings.HasPrefix(ref, "refs/heads/") {
+		environment.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	}
+	if environment.PullRequest == "" {
+		environment.PullRequest = os.Getenv("SYSTEM_PULLREQUEST_PULLREQUESTID")
+	}
+	return environment
+}
@@ -2,6 +2,8 @@ package common
 
 import (
 	"github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
 )
 
 type State string
@@ -46,6 +48,21 @@ func (s *State) IsWorstThan(state State) bool {
 	return s.Index() > state.Index()
 }
 
+// IsFinal returns true if the state is a final state, that is not changed by later updates of the build or deployment.
+func (s *State) IsFinal() bool {
+	return *s == Successful || *s == Failed || *s == Cancelled
+}
+
+// ParseState returns the state with the name, ignoring case.
+func ParseState(name string) (State, error) {
+	for _, state := range BestToWorst {
+		if strings.EqualFold(string(state), name) {
+			return state, nil
+		}
+	}
+	return Unknown, errorutils.CheckErrorf("Unsupported state %s, supported states are %s", name, BestToWorst)
+}
+
 type BuildInfoByNumber []entities.BuildInfo
 
 func (a BuildInfoByNumber) Len() int           { return len(a) }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	clientConfig "github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services/github"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	GitHubApiUrl = "https://api.github.com/"
+	// GitHubRequestConcurrency is the maximum number of issues that are requested concurrently.
+	GitHubRequestConcurrency = 4
+)
+
+type GitHubService struct {
+	client *jfroghttpclient.JfrogHttpClient
+	auth.ServiceDetails
+}
+
+func NewGitHubService(Url, Token string) (*GitHubService, error) {
+	details := NewGitHubDetails()
+	details.SetUrl(clientutils.AddTrailingSlashIfNeeded(Url))
+	details.SetAccessToken(Token)
+	configBuilder := clientConfig.NewConfigBuilder().SetServiceDetails(details)
+
+	config, err := configBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := jfroghttpclient.JfrogClientBuilder().
+		SetTimeout(config.GetHttpTimeout()).
+		SetRetries(config.GetHttpRetries()).
+		SetRetryWaitMilliSecs(config.GetHttpRetryWaitMilliSecs()).
+		SetHttpClient(config.GetHttpClient()).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return &GitHubService{client: client, ServiceDetails: details}, nil
+}
+
+// GetIssues resolves issue references in the format owner/repo#123 into affected issues, in the order of the references. The
+// issues are requested concurrently.
+func (gs *GitHubService) GetIssues(foundIssueKeys []string) ([]buildinfo.AffectedIssue, error) {
+	issues := make([]*github.Issue, len(foundIssueKeys))
+	errs := make([]error, len(foundIssueKeys))
+	semaphore := make(chan struct{}, GitHubRequestConcurrency)
+	var wg sync.WaitGroup
+	for i, key := range foundIssueKeys {
+		repository, number := SplitIssueReference(key, "#")
+		if repository == "" {
+			log.Warn("Skipping GitHub issue " + key + " since the repository is unknown")
+			continue
+		}
+		wg.Add(1)
+		go func(i int, repository, number string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			issues[i], errs[i] = gs.GetIssue(repository, number)
+		}(i, repository, number)
+	}
+	wg.Wait()
+
+	var foundIssues []buildinfo.AffectedIssue
+	for i, key := range foundIssueKeys {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if issue := issues[i]; issue != nil {
+			log.Info("Found GitHub issue: ", key)
+			foundIssues = append(foundIssues, buildinfo.AffectedIssue{
+				Key:        key,
+				Summary:    issue.Title,
+				Url:        issue.HtmlUrl,
+				Aggregated: false,
+			})
+		}
+	}
+	return foundIssues, nil
+}
+
+// GetIssue returns the issue with the given number, or nil if the issue doesn't exist.
+func (gs *GitHubService) GetIssue(repository, number string) (*github.Issue, error) {
+	clientDetails := gs.CreateHttpClientDetails()
+	utils.AddHeader("Accept", "application/vnd.github+json", &clientDetails.Headers)
+
+	url := gs.GetUrl() + "repos/" + repository + "/issues/" + number
+	log.Debug("Getting GitHub issue using request: ", url)
+	resp, body, _, err := gs.client.SendGet(url, false, &clientDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		issue := &github.Issue{}
+		if err := json.Unmarshal(body, &issue); err != nil {
+			return nil, err
+		}
+		return issue, nil
+	} else if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		log.Debug(fmt.Sprintf("Response from GitHub: %s.\n%s\n", resp.Status, body))
+		return nil, nil
+	} else {
+		return nil, errorutils.CheckErrorf(fmt.Sprintf("Response from GitHub: %s.\n%s\n", resp.Status, body))
+	}
+}
+
+// SplitIssueReference splits a reference like group/project#12 into the project path and the issue number.
+func SplitIssueReference(reference, separator string) (string, string) {
+	index := strings.LastIndex(reference, separator)
+	if index < 0 {
+		return "", reference
+	}
+	return reference[:index], reference[index+len(separator):]
+}
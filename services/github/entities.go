@@ -0,0 +1,14 @@
+package github
+
+type Issue struct {
+	Number      int          `json:"number"`
+	Title       string       `json:"title"`
+	State       string       `json:"state"`
+	HtmlUrl     string       `json:"html_url"`
+	PullRequest *PullRequest `json:"pull_request,omitempty"`
+}
+
+type PullRequest struct {
+	Url     string `json:"url"`
+	HtmlUrl string `json:"html_url"`
+}
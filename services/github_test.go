@@ -0,0 +1,139 @@
+package services
+
+import (
+	"encoding/json"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/marvelution/ext-build-info/services/github"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Returns a server that serves the GitHub issues by the path of the issue, like repos/owner/repo/issues/1.
+func newGitHubTestServer(t *testing.T, issues map[string]github.Issue) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issue, found := issues[strings.TrimPrefix(r.URL.Path, "/")]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(issue); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitHubGetIssues(t *testing.T) {
+	server := newGitHubTestServer(t, map[string]github.Issue{
+		"repos/owner/repo/issues/1":  {Number: 1, Title: "First", HtmlUrl: "https://github.com/owner/repo/issues/1"},
+		"repos/owner/other/issues/2": {Number: 2, Title: "Second", HtmlUrl: "https://github.com/owner/other/issues/2"},
+	})
+	service, err := NewGitHubService(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		keys   []string
+		issues []buildinfo.AffectedIssue
+	}{
+		{"issues", []string{"owner/repo#1", "owner/other#2"}, []buildinfo.AffectedIssue{
+			{Key: "owner/repo#1", Summary: "First", Url: "https://github.com/owner/repo/issues/1"},
+			{Key: "owner/other#2", Summary: "Second", Url: "https://github.com/owner/other/issues/2"}}},
+		{"unknown issue", []string{"owner/repo#3"}, nil},
+		{"unknown repository", []string{"#1"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issues, err := service.GetIssues(test.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(issues, test.issues) {
+				t.Errorf("GetIssues(%v) = %v, want %v", test.keys, issues, test.issues)
+			}
+		})
+	}
+}
+
+func TestGitHubGetIssuesConcurrently(t *testing.T) {
+	var lock sync.Mutex
+	running, maxConcurrent := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		running++
+		if running > maxConcurrent {
+			maxConcurrent = running
+		}
+		lock.Unlock()
+		defer func() {
+			lock.Lock()
+			running--
+			lock.Unlock()
+		}()
+		// Keep the request running, so concurrent requests overlap.
+		time.Sleep(20 * time.Millisecond)
+		number, err := strconv.Atoi(path.Base(r.URL.Path))
+		if err != nil || number%5 == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err = json.NewEncoder(w).Encode(github.Issue{Number: number, Title: "Issue " + strconv.Itoa(number)}); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	service, err := NewGitHubService(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	var expected []buildinfo.AffectedIssue
+	for number := 1; number <= 20; number++ {
+		key := "owner/repo#" + strconv.Itoa(number)
+		keys = append(keys, key)
+		if number%5 != 0 {
+			expected = append(expected, buildinfo.AffectedIssue{Key: key, Summary: "Issue " + strconv.Itoa(number)})
+		}
+	}
+	issues, err := service.GetIssues(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("GetIssues() = %v, want %v", issues, expected)
+	}
+	if maxConcurrent > GitHubRequestConcurrency || maxConcurrent < 2 {
+		t.Errorf("GetIssues() ran %d concurrent requests, want between 2 and %d", maxConcurrent, GitHubRequestConcurrency)
+	}
+}
+
+func TestSplitIssueReference(t *testing.T) {
+	tests := []struct {
+		reference  string
+		repository string
+		number     string
+	}{
+		{"#1", "", "1"},
+		{"owner/repo#1", "owner/repo", "1"},
+		{"1", "", "1"},
+	}
+	for _, test := range tests {
+		t.Run(test.reference, func(t *testing.T) {
+			repository, number := SplitIssueReference(test.reference, "#")
+			if repository != test.repository || number != test.number {
+				t.Errorf("SplitIssueReference(%s) = %s, %s, want %s, %s", test.reference, repository, number, test.repository,
+					test.number)
+			}
+		})
+	}
+}
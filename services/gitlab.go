@@ -0,0 +1,164 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/auth"
+	clientConfig "github.com/jfrog/jfrog-client-go/config"
+	"github.com/jfrog/jfrog-client-go/http/jfroghttpclient"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services/gitlab"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+const (
+	GitLabUrl = "https://gitlab.com/"
+	// GitLabIidBatchSize is the maximum number of issues or merge requests requested by iid in a single request.
+	GitLabIidBatchSize = 100
+)
+
+var gitLabReferenceRegex = regexp.MustCompile(`^(.*?)([#!])(\d+)$`)
+
+// SplitGitLabReference splits a reference like group/project!34 into the project path, the reference kind and the iid.
+func SplitGitLabReference(reference string) (string, string, string) {
+	parts := gitLabReferenceRegex.FindStringSubmatch(reference)
+	if parts == nil {
+		return "", "", ""
+	}
+	return parts[1], parts[2], parts[3]
+}
+
+type GitLabService struct {
+	client *jfroghttpclient.JfrogHttpClient
+	auth.ServiceDetails
+}
+
+func NewGitLabService(Url, Token string) (*GitLabService, error) {
+	details := NewGitLabDetails()
+	details.SetUrl(clientutils.AddTrailingSlashIfNeeded(Url))
+	details.SetAccessToken(Token)
+	configBuilder := clientConfig.NewConfigBuilder().SetServiceDetails(details)
+
+	config, err := configBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := jfroghttpclient.JfrogClientBuilder().
+		SetTimeout(config.GetHttpTimeout()).
+		SetRetries(config.GetHttpRetries()).
+		SetRetryWaitMilliSecs(config.GetHttpRetryWaitMilliSecs()).
+		SetHttpClient(config.GetHttpClient()).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return &GitLabService{client: client, ServiceDetails: details}, nil
+}
+
+// GetIssues resolves issue references like group/project#12 and merge request references like group/project!34 into affected
+// issues, in the order of the references. The issues and merge requests of a project are requested in batches by their iids.
+func (gs *GitLabService) GetIssues(foundIssueKeys []string) ([]buildinfo.AffectedIssue, error) {
+	type projectKind struct{ project, kind string }
+	var groups []projectKind
+	iids := map[projectKind][]string{}
+	for _, key := range foundIssueKeys {
+		issueProject, kind, iid := SplitGitLabReference(key)
+		if iid == "" {
+			log.Warn("Skipping GitLab reference " + key + " since it is not a valid reference")
+			continue
+		}
+		if issueProject == "" {
+			log.Warn("Skipping GitLab reference " + key + " since the project is unknown")
+			continue
+		}
+		group := projectKind{issueProject, kind}
+		if _, found := iids[group]; !found {
+			groups = append(groups, group)
+		}
+		iids[group] = append(iids[group], iid)
+	}
+
+	references := map[string]buildinfo.AffectedIssue{}
+	for _, group := range groups {
+		for start := 0; start < len(iids[group]); start += GitLabIidBatchSize {
+			end := start + GitLabIidBatchSize
+			if end > len(iids[group]) {
+				end = len(iids[group])
+			}
+			found, err := gs.getReferences(group.project, group.kind, iids[group][start:end])
+			if err != nil {
+				return nil, err
+			}
+			for _, reference := range found {
+				references[reference.Key] = reference
+			}
+		}
+	}
+
+	var foundIssues []buildinfo.AffectedIssue
+	for _, key := range foundIssueKeys {
+		if reference, found := references[key]; found {
+			log.Info("Found GitLab reference: ", key)
+			foundIssues = append(foundIssues, reference)
+		}
+	}
+	return foundIssues, nil
+}
+
+// Returns the issues, or the merge requests for the ! kind, of the project with the iids. Unknown iids are left out.
+func (gs *GitLabService) getReferences(project, kind string, iids []string) ([]buildinfo.AffectedIssue, error) {
+	query := "?" + url.Values{"iids[]": iids, "per_page": {strconv.Itoa(GitLabIidBatchSize)}}.Encode()
+	var references []buildinfo.AffectedIssue
+	addReference := func(iid int, title, webUrl string) {
+		references = append(references, buildinfo.AffectedIssue{
+			Key:        project + kind + strconv.Itoa(iid),
+			Summary:    title,
+			Url:        webUrl,
+			Aggregated: false,
+		})
+	}
+	if kind == "!" {
+		var mergeRequests []gitlab.MergeRequest
+		if _, err := gs.GetRequest("projects/"+url.PathEscape(project)+"/merge_requests"+query, &mergeRequests); err != nil {
+			return nil, err
+		}
+		for _, mergeRequest := range mergeRequests {
+			addReference(mergeRequest.Iid, mergeRequest.Title, mergeRequest.WebUrl)
+		}
+	} else {
+		var issues []gitlab.Issue
+		if _, err := gs.GetRequest("projects/"+url.PathEscape(project)+"/issues"+query, &issues); err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			addReference(issue.Iid, issue.Title, issue.WebUrl)
+		}
+	}
+	return references, nil
+}
+
+// GetRequest gets the given v4 api resource, returns false if the resource doesn't exist.
+func (gs *GitLabService) GetRequest(resource string, response any) (bool, error) {
+	clientDetails := gs.CreateHttpClientDetails()
+	fullUrl := gs.GetUrl() + "api/v4/" + resource
+	log.Debug("Getting GitLab resource using request: ", fullUrl)
+	resp, body, _, err := gs.client.SendGet(fullUrl, false, &clientDetails)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return true, json.Unmarshal(body, response)
+	} else if resp.StatusCode == http.StatusNotFound {
+		log.Debug(fmt.Sprintf("Response from GitLab (%s): %s.\n%s\n", fullUrl, resp.Status, body))
+		return false, nil
+	} else {
+		return false, errorutils.CheckErrorf(fmt.Sprintf("Response from GitLab (%s): %s.\n%s\n", fullUrl, resp.Status, body))
+	}
+}
@@ -0,0 +1,17 @@
+package gitlab
+
+type Issue struct {
+	Id     int    `json:"id"`
+	Iid    int    `json:"iid"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	WebUrl string `json:"web_url"`
+}
+
+type MergeRequest struct {
+	Id     int    `json:"id"`
+	Iid    int    `json:"iid"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	WebUrl string `json:"web_url"`
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"encoding/json"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Returns a server that lists the GitLab issues and merge requests by their iids, the titles are keyed by the path of the
+// resource, like projects/group%2Fproject/issues/1. The requests are sent to the given channel, if any.
+func newGitLabTestServer(t *testing.T, titles map[string]string, requests chan<- *http.Request) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			requests <- r
+		}
+		resource := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/")
+		var references []map[string]any
+		for _, iid := range r.URL.Query()["iids[]"] {
+			if title, found := titles[resource+"/"+iid]; found {
+				number, _ := strconv.Atoi(iid)
+				references = append(references, map[string]any{"iid": number, "title": title,
+					"web_url": "https://gitlab.com/" + resource + "/" + iid})
+			}
+		}
+		if err := json.NewEncoder(w).Encode(references); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitLabGetIssues(t *testing.T) {
+	server := newGitLabTestServer(t, map[string]string{
+		"projects/group%2Fproject/issues/1":               "First",
+		"projects/group%2Fproject/merge_requests/2":       "Second",
+		"projects/group%2Fsub%2Fproject/issues/3":         "Third",
+		"projects/group%2Fsub%2Fproject/merge_requests/4": "Fourth",
+	}, nil)
+	service, err := NewGitLabService(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		keys   []string
+		issues []buildinfo.AffectedIssue
+	}{
+		{"issue", []string{"group/project#1"}, []buildinfo.AffectedIssue{
+			{Key: "group/project#1", Summary: "First", Url: "https://gitlab.com/projects/group%2Fproject/issues/1"}}},
+		{"merge request", []string{"group/project!2"}, []buildinfo.AffectedIssue{
+			{Key: "group/project!2", Summary: "Second", Url: "https://gitlab.com/projects/group%2Fproject/merge_requests/2"}}},
+		{"subgroup", []string{"group/sub/project#3", "group/sub/project!4"}, []buildinfo.AffectedIssue{
+			{Key: "group/sub/project#3", Summary: "Third", Url: "https://gitlab.com/projects/group%2Fsub%2Fproject/issues/3"},
+			{Key: "group/sub/project!4", Summary: "Fourth", Url: "https://gitlab.com/projects/group%2Fsub%2Fproject/merge_requests/4"}}},
+		{"issue and merge request with the same iid", []string{"group/project#2", "group/project!1"}, nil},
+		{"invalid reference", []string{"group/project"}, nil},
+		{"unknown project", []string{"#1"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issues, err := service.GetIssues(test.keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(issues, test.issues) {
+				t.Errorf("GetIssues(%v) = %v, want %v", test.keys, issues, test.issues)
+			}
+		})
+	}
+}
+
+func TestGitLabGetIssuesInBatches(t *testing.T) {
+	titles := map[string]string{"projects/group%2Fproject/merge_requests/1": "Merge request 1"}
+	keys := []string{"group/project!1"}
+	var expected []buildinfo.AffectedIssue
+	for iid := GitLabIidBatchSize + 50; iid > 0; iid-- {
+		key := "group/project#" + strconv.Itoa(iid)
+		keys = append(keys, key)
+		if iid%10 == 0 {
+			continue
+		}
+		resource := "projects/group%2Fproject/issues/" + strconv.Itoa(iid)
+		titles[resource] = "Issue " + strconv.Itoa(iid)
+		expected = append(expected, buildinfo.AffectedIssue{Key: key, Summary: titles[resource], Url: "https://gitlab.com/" + resource})
+	}
+	expected = append([]buildinfo.AffectedIssue{{Key: "group/project!1", Summary: "Merge request 1",
+		Url: "https://gitlab.com/projects/group%2Fproject/merge_requests/1"}}, expected...)
+
+	requests := make(chan *http.Request, 10)
+	server := newGitLabTestServer(t, titles, requests)
+	service, err := NewGitLabService(server.URL, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := service.GetIssues(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("GetIssues() = %v, want %v", issues, expected)
+	}
+
+	close(requests)
+	var batches []string
+	for request := range requests {
+		batches = append(batches, request.URL.EscapedPath()+" "+strconv.Itoa(len(request.URL.Query()["iids[]"])))
+	}
+	if expectedBatches := []string{
+		"/api/v4/projects/group%2Fproject/merge_requests 1",
+		"/api/v4/projects/group%2Fproject/issues " + strconv.Itoa(GitLabIidBatchSize),
+		"/api/v4/projects/group%2Fproject/issues 50",
+	}; !reflect.DeepEqual(batches, expectedBatches) {
+		t.Errorf("GetIssues() requested %v, want %v", batches, expectedBatches)
+	}
+}
+
+func TestSplitGitLabReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		project   string
+		kind      string
+		iid       string
+	}{
+		{"#1", "", "#", "1"},
+		{"!2", "", "!", "2"},
+		{"group/sub/project#3", "group/sub/project", "#", "3"},
+		{"group/project!4", "group/project", "!", "4"},
+		{"group/project", "", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.reference, func(t *testing.T) {
+			project, kind, iid := SplitGitLabReference(test.reference)
+			if project != test.project || kind != test.kind || iid != test.iid {
+				t.Errorf("SplitGitLabReference(%s) = %s, %s, %s, want %s, %s, %s", test.reference, project, kind, iid, test.project,
+					test.kind, test.iid)
+			}
+		})
+	}
+}
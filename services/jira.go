@@ -13,21 +13,46 @@ import (
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/marvelution/ext-build-info/services/jira"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// JiraIssueBatchSize is the number of issue keys searched for in a single JQL query.
+	JiraIssueBatchSize = 50
+	// JiraSearchPageSize is the maximum number of issues returned per search request.
+	JiraSearchPageSize = 100
+	// JiraSearchConcurrency is the maximum number of searches that run concurrently.
+	JiraSearchConcurrency = 4
+	// JiraBuildsBatchSize is the maximum number of builds accepted by Jira in a single bulk request.
+	JiraBuildsBatchSize = 100
+	// JiraDefaultRetryAfter is the duration to wait when Jira rate limits a request without a Retry-After header.
+	JiraDefaultRetryAfter = 10 * time.Second
 )
 
 type JiraService struct {
-	client  *jfroghttpclient.JfrogHttpClient
-	cloudId string
-	dryRun  bool
+	client         *jfroghttpclient.JfrogHttpClient
+	cloudId        string
+	dryRun         bool
+	deploymentType string
+	detectOnce     sync.Once
+	detectErr      error
 	auth.ServiceDetails
 }
 
+// NewJiraService creates a Jira service that uses basic authentication, or bearer authentication using a personal access token
+// if no username is given. The latter is only supported by Jira Server and Data Center.
 func NewJiraService(Url, Username, Token string) (*JiraService, error) {
 	details := NewJiraDetails()
 	details.SetUrl(clientutils.AddTrailingSlashIfNeeded(Url))
-	details.SetUser(Username)
-	details.SetPassword(Token)
+	if Username == "" {
+		details.SetAccessToken(Token)
+	} else {
+		details.SetUser(Username)
+		details.SetPassword(Token)
+	}
 	configBuilder := clientConfig.NewConfigBuilder().SetServiceDetails(details)
 
 	config, err := configBuilder.Build()
@@ -100,17 +125,67 @@ func NewOAuthJiraService(Url, ClientId, Secret string, dryRun bool) (*JiraServic
 			return nil, errorutils.CheckErrorf(fmt.Sprintf("Failed getting an access token: %s.\n%s\n", resp.Status, body))
 		}
 	}
-	return &JiraService{client: client, ServiceDetails: details, dryRun: dryRun}, nil
+	// OAuth apps are only supported by Jira Cloud.
+	return &JiraService{client: client, ServiceDetails: details, dryRun: dryRun, deploymentType: jira.DeploymentTypeCloud}, nil
 }
 
 func (js *JiraService) GetVersion() (string, error) {
 	info := &jira.ServerInfo{}
-	if err := js.GetRequest("rest/api/3/serverInfo", &info); err != nil {
+	if err := js.GetRequest("rest/api/2/serverInfo", &info); err != nil {
 		return "", err
 	}
 	return info.Version, nil
 }
 
+// GetDeploymentType returns the deployment type of Jira, being Cloud, Server or DataCenter.
+func (js *JiraService) GetDeploymentType() (string, error) {
+	js.detectOnce.Do(func() {
+		if js.deploymentType != "" {
+			return
+		}
+		info := &jira.ServerInfo{}
+		if js.detectErr = js.GetRequest("rest/api/2/serverInfo", &info); js.detectErr == nil {
+			js.deploymentType = info.DeploymentType
+			if js.deploymentType == "" {
+				// Old Jira Server versions don't provide the deployment type.
+				js.deploymentType = jira.DeploymentTypeServer
+			}
+			log.Debug("Detected Jira deployment type: ", js.deploymentType)
+		}
+	})
+	return js.deploymentType, js.detectErr
+}
+
+// IsCloud returns true if Jira is a Jira Cloud site.
+func (js *JiraService) IsCloud() (bool, error) {
+	deploymentType, err := js.GetDeploymentType()
+	return deploymentType == jira.DeploymentTypeCloud, err
+}
+
+// Returns an error if Jira is not a Jira Cloud site, as the builds and deployments APIs are only available on Jira Cloud.
+func (js *JiraService) requireCloud(api string) error {
+	deploymentType, err := js.GetDeploymentType()
+	if err != nil {
+		return err
+	}
+	if deploymentType != jira.DeploymentTypeCloud {
+		return errorutils.CheckErrorf("The Jira %s API is only available on Jira Cloud, not on Jira %s, use remote links instead", api, deploymentType)
+	}
+	return nil
+}
+
+// Returns the path of the REST api to use, version 3 is only available on Jira Cloud.
+func (js *JiraService) getRestApi() (string, error) {
+	cloud, err := js.IsCloud()
+	if err != nil {
+		return "", err
+	}
+	if cloud {
+		return "rest/api/3/", nil
+	}
+	return "rest/api/2/", nil
+}
+
 func (js *JiraService) GetCloudId() (string, error) {
 	if js.cloudId != "" {
 		return js.cloudId, nil
@@ -137,62 +212,341 @@ func (js *JiraService) GetRequest(url string, request any) error {
 	}
 }
 
+// GetProjectKeys returns the keys of all projects the user has access to.
+func (js *JiraService) GetProjectKeys() ([]string, error) {
+	var projectKeys []string
+	cloud, err := js.IsCloud()
+	if err != nil {
+		return nil, err
+	}
+	if !cloud {
+		// Jira Server and Data Center don't support searching projects, but list all projects at once.
+		var projects []jira.Project
+		if err := js.GetRequest("rest/api/2/project", &projects); err != nil {
+			return nil, err
+		}
+		for _, project := range projects {
+			projectKeys = append(projectKeys, project.Key)
+		}
+		log.Debug("Found Jira projects: ", projectKeys)
+		return projectKeys, nil
+	}
+	for startAt := 0; ; {
+		searchResult := &jira.ProjectSearchResult{}
+		if err := js.GetRequest(fmt.Sprintf("rest/api/3/project/search?startAt=%d&maxResults=50", startAt), searchResult); err != nil {
+			return nil, err
+		}
+		for _, project := range searchResult.Values {
+			projectKeys = append(projectKeys, project.Key)
+		}
+		startAt += len(searchResult.Values)
+		if searchResult.IsLast || len(searchResult.Values) == 0 {
+			break
+		}
+	}
+	log.Debug("Found Jira projects: ", projectKeys)
+	return projectKeys, nil
+}
+
+// GetIssues resolves the issue keys into affected issues, in the order of the issue keys.
 func (js *JiraService) GetIssues(foundIssueKeys []string) ([]buildinfo.AffectedIssue, error) {
+	issues, _, err := js.GetIssuesWithFields(foundIssueKeys, nil)
+	return issues, err
+}
+
+// GetIssuesWithFields resolves the issue keys into affected issues, in the order of the issue keys, and the values of the extra
+// fields of each issue by issue key. Keys are searched in batches that run concurrently, and the results of each batch are paged
+// through. Keys Jira refused are reported as warnings.
+func (js *JiraService) GetIssuesWithFields(foundIssueKeys []string, fields []string) ([]buildinfo.AffectedIssue, map[string]map[string]string, error) {
+	fieldValues := map[string]map[string]string{}
 	if len(foundIssueKeys) == 0 {
-		return []buildinfo.AffectedIssue{}, nil
+		return []buildinfo.AffectedIssue{}, fieldValues, nil
 	}
 
-	request := &jira.SearchRequest{
-		Jql:           "issue IN (" + strings.Join(foundIssueKeys[:], ",") + ")",
-		Fields:        []string{"key", "summary"},
-		StartAt:       0,
-		MaxResults:    100,
-		ValidateQuery: "warn",
+	var batches [][]string
+	for start := 0; start < len(foundIssueKeys); start += JiraIssueBatchSize {
+		end := start + JiraIssueBatchSize
+		if end > len(foundIssueKeys) {
+			end = len(foundIssueKeys)
+		}
+		batches = append(batches, foundIssueKeys[start:end])
 	}
 
-	content, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
+	results := make([][]jira.Issue, len(batches))
+	errs := make([]error, len(batches))
+	semaphore := make(chan struct{}, JiraSearchConcurrency)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i], errs[i] = js.searchIssues("issue IN ("+strings.Join(batch, ",")+")", append([]string{"key", "summary"}, fields...))
+		}(i, batch)
+	}
+	wg.Wait()
+
+	issues := map[string]jira.Issue{}
+	var otherIssues []jira.Issue
+	for i, result := range results {
+		if errs[i] != nil {
+			return nil, nil, errs[i]
+		}
+		for _, issue := range result {
+			issues[issue.Key] = issue
+			otherIssues = append(otherIssues, issue)
+		}
 	}
 
-	log.Info("Searching Jira using request:", string(content))
+	// Issues are returned in the order of the keys, followed by issues that were found by another key, e.g. moved issues.
+	var foundIssues []buildinfo.AffectedIssue
+	added := map[string]bool{}
+	addIssue := func(issue jira.Issue) {
+		if added[issue.Key] {
+			return
+		}
+		added[issue.Key] = true
+		log.Info("Found Jira issue: ", issue.Key)
+		if len(fields) > 0 {
+			fieldValues[issue.Key] = map[string]string{}
+			for _, field := range fields {
+				fieldValues[issue.Key][field] = issue.Fields.GetValue(field)
+			}
+		}
+		foundIssues = append(foundIssues, buildinfo.AffectedIssue{
+			Key:        issue.Key,
+			Summary:    issue.Fields.Summary,
+			Url:        js.GetUrl() + "browse/" + issue.Key,
+			Aggregated: false,
+		})
+	}
+	for _, key := range foundIssueKeys {
+		if issue, found := issues[key]; found {
+			addIssue(issue)
+		}
+	}
+	for _, issue := range otherIssues {
+		addIssue(issue)
+	}
+	return foundIssues, fieldValues, nil
+}
 
-	clientDetails := js.CreateHttpClientDetails()
-	utils.SetContentType("application/json", &clientDetails.Headers)
-	resp, body, err := js.client.SendPost(js.GetUrl()+"rest/api/3/search", content, &clientDetails)
+// Returns all issues matching the jql, paging through the search results.
+func (js *JiraService) searchIssues(jql string, fields []string) ([]jira.Issue, error) {
+	restApi, err := js.getRestApi()
 	if err != nil {
 		return nil, err
 	}
+	var issues []jira.Issue
+	for startAt := 0; ; {
+		request := &jira.SearchRequest{
+			Jql:           jql,
+			Fields:        fields,
+			StartAt:       startAt,
+			MaxResults:    JiraSearchPageSize,
+			ValidateQuery: "warn",
+		}
+
+		content, err := json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("Searching Jira using request:", string(content))
+
+		clientDetails := js.CreateHttpClientDetails()
+		utils.SetContentType("application/json", &clientDetails.Headers)
+		resp, body, err := js.client.SendPost(js.GetUrl()+restApi+"search", content, &clientDetails)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errorutils.CheckErrorf(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
+		}
 
-	if resp.StatusCode == http.StatusOK {
 		searchResult := &jira.SearchResult{}
 		if err := json.Unmarshal(body, &searchResult); err != nil {
 			return nil, err
 		}
+		for _, warning := range searchResult.WarningMessages {
+			log.Warn("Jira: " + warning)
+		}
 
-		var foundIssues []buildinfo.AffectedIssue
-		for _, issue := range searchResult.Issues {
-			log.Info("Found Jira issue: ", issue)
-			foundIssues = append(foundIssues, buildinfo.AffectedIssue{
-				Key:        issue.Key,
-				Summary:    issue.Fields.Summary,
-				Url:        js.GetUrl() + "browse/" + issue.Key,
-				Aggregated: false,
-			})
+		issues = append(issues, searchResult.Issues...)
+		startAt += len(searchResult.Issues)
+		if len(searchResult.Issues) == 0 || startAt >= searchResult.Total {
+			return issues, nil
 		}
+	}
+}
+
+// SetDryRun enables to only log the requests that would change Jira.
+func (js *JiraService) SetDryRun(dryRun bool) *JiraService {
+	js.dryRun = dryRun
+	return js
+}
+
+// CreateOrUpdateRemoteLink creates the remote link on the issue, or updates the remote link with the same global id.
+// False is returned if the issue doesn't exist.
+func (js *JiraService) CreateOrUpdateRemoteLink(issueKey string, remoteLink jira.RemoteLink) (bool, error) {
+	content, err := json.Marshal(remoteLink)
+	if err != nil {
+		return false, err
+	}
+
+	url := js.GetUrl() + "rest/api/2/issue/" + issueKey + "/remotelink"
+	if js.dryRun {
+		log.Info("Dry-running request to Jira ("+url+"):", string(content))
+		return true, nil
+	}
 
-		return foundIssues, nil
+	log.Debug("Sending remote link to Jira using request ("+url+"):", string(content))
+	clientDetails := js.CreateHttpClientDetails()
+	utils.SetContentType("application/json", &clientDetails.Headers)
+	resp, body, err := js.client.SendPost(url, content, &clientDetails)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		return true, nil
+	} else if resp.StatusCode == http.StatusNotFound {
+		log.Debug(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
+		return false, nil
 	} else {
-		return nil, errorutils.CheckErrorf(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
+		return false, errorutils.CheckErrorf(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
 	}
 }
 
+// GetTransitions returns the transitions that are available for the issue in its current status.
+func (js *JiraService) GetTransitions(issueKey string) ([]jira.Transition, error) {
+	response := &jira.TransitionsResponse{}
+	if err := js.GetRequest("rest/api/2/issue/"+issueKey+"/transitions", response); err != nil {
+		return nil, err
+	}
+	return response.Transitions, nil
+}
+
+// TransitionIssue transitions the issue using the transition with the given id.
+func (js *JiraService) TransitionIssue(issueKey, transitionId string) error {
+	return js.sendRequest(http.MethodPost, "rest/api/2/issue/"+issueKey+"/transitions",
+		jira.TransitionRequest{Transition: jira.TransitionId{Id: transitionId}}, nil)
+}
+
+// AddComment adds a comment, in Jira wiki markup, to the issue.
+func (js *JiraService) AddComment(issueKey, comment string) error {
+	return js.sendRequest(http.MethodPost, "rest/api/2/issue/"+issueKey+"/comment", jira.CommentRequest{Body: comment}, nil)
+}
+
+// GetProject returns the project with the given key.
+func (js *JiraService) GetProject(projectKey string) (*jira.Project, error) {
+	project := &jira.Project{}
+	if err := js.GetRequest("rest/api/2/project/"+projectKey, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// GetVersions returns all the versions of the project.
+func (js *JiraService) GetVersions(projectKey string) ([]jira.Version, error) {
+	var versions []jira.Version
+	if err := js.GetRequest("rest/api/2/project/"+projectKey+"/versions", &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// FindOrCreateVersion returns the version of the project with the given name, the version is created if the project doesn't
+// have it yet.
+func (js *JiraService) FindOrCreateVersion(projectKey, name string) (*jira.Version, error) {
+	versions, err := js.GetVersions(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		if strings.EqualFold(version.Name, name) {
+			return &version, nil
+		}
+	}
+	project, err := js.GetProject(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	version := &jira.Version{Name: name, ProjectId: json.Number(project.Id)}
+	if err := js.sendRequest(http.MethodPost, "rest/api/2/version", version, version); err != nil {
+		return nil, err
+	}
+	log.Info("Created version " + name + " in project " + projectKey)
+	return version, nil
+}
+
+// AddFixVersion adds the version, by name, to the fix versions of the issue.
+func (js *JiraService) AddFixVersion(issueKey, versionName string) error {
+	request := jira.IssueUpdateRequest{Update: jira.IssueUpdate{
+		FixVersions: []jira.VersionOperation{{Add: &jira.VersionName{Name: versionName}}},
+	}}
+	return js.sendRequest(http.MethodPut, "rest/api/2/issue/"+issueKey, request, nil)
+}
+
+// ReleaseVersion marks the version as released today, unless it is already released.
+func (js *JiraService) ReleaseVersion(version *jira.Version) error {
+	if version.Released {
+		return nil
+	}
+	request := jira.Version{Released: true, ReleaseDate: time.Now().Format("2006-01-02")}
+	return js.sendRequest(http.MethodPut, "rest/api/2/version/"+version.Id, request, nil)
+}
+
+// Sends the request to Jira and reads the response into the given response, if any. Only logs the request when dry-running.
+func (js *JiraService) sendRequest(method, url string, request, response any) error {
+	content, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	if js.dryRun {
+		log.Info("Dry-running "+method+" request to Jira ("+js.GetUrl()+url+"):", string(content))
+		return nil
+	}
+
+	log.Debug("Sending "+method+" request to Jira ("+js.GetUrl()+url+"):", string(content))
+	clientDetails := js.CreateHttpClientDetails()
+	utils.SetContentType("application/json", &clientDetails.Headers)
+	var resp *http.Response
+	var body []byte
+	if method == http.MethodPut {
+		resp, body, err = js.client.SendPut(js.GetUrl()+url, content, &clientDetails)
+	} else {
+		resp, body, err = js.client.SendPost(js.GetUrl()+url, content, &clientDetails)
+	}
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errorutils.CheckErrorf(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
+	}
+	if response != nil && len(body) > 0 {
+		return json.Unmarshal(body, response)
+	}
+	return nil
+}
+
 func (js *JiraService) SendBuildInfo(buildInfo jira.BuildInfo) (*jira.BuildInfoResponse, error) {
+	return js.SendBuildInfos([]jira.BuildInfo{buildInfo})
+}
+
+// SendBuildInfos sends the builds to Jira in a single bulk request, at most JiraBuildsBatchSize builds can be sent at once.
+// A RateLimitError is returned if Jira rejected the request because of rate limiting.
+func (js *JiraService) SendBuildInfos(buildInfos []jira.BuildInfo) (*jira.BuildInfoResponse, error) {
+	if err := js.requireCloud("builds"); err != nil {
+		return nil, err
+	}
+	if len(buildInfos) > JiraBuildsBatchSize {
+		return nil, errorutils.CheckErrorf("Unable to send %d builds, Jira accepts at most %d builds per request", len(buildInfos),
+			JiraBuildsBatchSize)
+	}
 	request := jira.BuildInfoRequest{
 		Properties: map[string]string{},
-		Builds: []jira.BuildInfo{
-			buildInfo,
-		},
+		Builds:     buildInfos,
 		ProviderMetadata: jira.ProviderMetadata{
 			Product: "Jfrog Pipelines",
 		},
@@ -213,14 +567,14 @@ func (js *JiraService) SendBuildInfo(buildInfo jira.BuildInfo) (*jira.BuildInfoR
 	url := "https://api.atlassian.com/jira/builds/0.1/cloud/" + cloudId + "/bulk"
 	if js.dryRun {
 		log.Info("Dry-running request to Jira ("+url+"):", string(content))
-		return &jira.BuildInfoResponse{
-			AcceptedBuilds: []jira.BuildKey{{
+		response := &jira.BuildInfoResponse{}
+		for _, buildInfo := range buildInfos {
+			response.AcceptedBuilds = append(response.AcceptedBuilds, jira.BuildKey{
 				PipelineId:  buildInfo.PipelineId,
 				BuildNumber: buildInfo.BuildNumber,
-			}},
-			RejectedBuilds:   nil,
-			UnknownIssueKeys: nil,
-		}, nil
+			})
+		}
+		return response, nil
 	} else {
 		log.Debug("Sending build-info to Jira using request ("+url+"):", string(content))
 		resp, body, err := js.client.SendPost(url, content, &clientDetails)
@@ -237,13 +591,37 @@ func (js *JiraService) SendBuildInfo(buildInfo jira.BuildInfo) (*jira.BuildInfoR
 			log.Debug(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
 
 			return response, nil
+		} else if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, newRateLimitError(resp)
 		} else {
 			return nil, errorutils.CheckErrorf(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
 		}
 	}
 }
 
+// RateLimitError is returned when Jira rejects a request because of rate limiting, RetryAfter is the duration to wait before
+// retrying the request.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("Rate limited by Jira, retry after %s", e.RetryAfter)
+}
+
+// Returns the RateLimitError of the response, using the Retry-After header if Jira provided it.
+func newRateLimitError(resp *http.Response) *RateLimitError {
+	retryAfter := JiraDefaultRetryAfter
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return &RateLimitError{RetryAfter: retryAfter}
+}
+
 func (js *JiraService) SendDeploymentInfo(deploymentInfo jira.DeploymentInfo) (*jira.DeploymentInfoResponse, error) {
+	if err := js.requireCloud("deployments"); err != nil {
+		return nil, err
+	}
 	request := jira.DeploymentInfoRequest{
 		Properties: map[string]string{},
 		Deployments: []jira.DeploymentInfo{
@@ -300,3 +678,39 @@ func (js *JiraService) SendDeploymentInfo(deploymentInfo jira.DeploymentInfo) (*
 		}
 	}
 }
+
+// GetDeploymentGatingStatus returns the gating status of the deployment, that is initiated by sending the deployment with the
+// jira.InitiateDeploymentGating command.
+func (js *JiraService) GetDeploymentGatingStatus(pipelineId, environmentId string, deploymentSequenceNumber int64) (*jira.GatingStatusResponse, error) {
+	if err := js.requireCloud("deployments"); err != nil {
+		return nil, err
+	}
+	cloudId, err := js.GetCloudId()
+	if err != nil {
+		return nil, err
+	}
+	url := "https://api.atlassian.com/jira/deployments/0.1/cloud/" + cloudId + "/pipelines/" + pipelineId + "/environments/" +
+		environmentId + "/deployments/" + strconv.FormatInt(deploymentSequenceNumber, 10) + "/gating-status"
+	if js.dryRun {
+		log.Info("Dry-running request to Jira (" + url + ")")
+		return &jira.GatingStatusResponse{DeploymentSequenceNumber: deploymentSequenceNumber, PipelineId: pipelineId,
+			EnvironmentId: environmentId, GatingStatus: jira.GatingAllowed}, nil
+	}
+
+	log.Debug("Getting deployment gating status from Jira (" + url + ")")
+	clientDetails := js.CreateHttpClientDetails()
+	resp, body, _, err := js.client.SendGet(url, false, &clientDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(resp)
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, errorutils.CheckErrorf(fmt.Sprintf("Response from Jira: %s.\n%s\n", resp.Status, body))
+	}
+	response := &jira.GatingStatusResponse{}
+	if err = json.Unmarshal(body, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -1,13 +1,23 @@
 package jira
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/marvelution/ext-build-info/services/common"
+	"strings"
 	"time"
 )
 
+const (
+	DeploymentTypeCloud      = "Cloud"
+	DeploymentTypeServer     = "Server"
+	DeploymentTypeDataCenter = "DataCenter"
+)
+
 type ServerInfo struct {
 	Version        string `json:"version"`
 	VersionNumbers []int  `json:"versionNumbers"`
+	DeploymentType string `json:"deploymentType"`
 }
 
 type CloudIdResponse struct {
@@ -31,13 +41,68 @@ type SearchResult struct {
 	WarningMessages []string `json:"warningMessages,omitempty"`
 }
 
+type ProjectSearchResult struct {
+	StartAt    int       `json:"startAt,omitempty"`
+	MaxResults int       `json:"maxResults,omitempty"`
+	Total      int       `json:"total,omitempty"`
+	IsLast     bool      `json:"isLast,omitempty"`
+	Values     []Project `json:"values,omitempty"`
+}
+
+type Project struct {
+	Id   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
 type Issue struct {
 	Key    string      `json:"key"`
 	Fields IssueFields `json:"fields"`
 }
 
 type IssueFields struct {
-	Summary string `json:"summary"`
+	Summary string         `json:"summary"`
+	All     map[string]any `json:"-"`
+}
+
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	type issueFields IssueFields
+	if err := json.Unmarshal(data, (*issueFields)(f)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &f.All)
+}
+
+// GetValue returns the value of the field as text. Objects, like a status or version, are represented by their key, name or
+// display name, and lists are comma separated.
+func (f *IssueFields) GetValue(field string) string {
+	return formatFieldValue(f.All[field])
+}
+
+func formatFieldValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []any:
+		var values []string
+		for _, item := range value {
+			if formatted := formatFieldValue(item); formatted != "" {
+				values = append(values, formatted)
+			}
+		}
+		return strings.Join(values, ",")
+	case map[string]any:
+		for _, name := range []string{"key", "name", "displayName", "value"} {
+			if nameValue, found := value[name]; found {
+				return formatFieldValue(nameValue)
+			}
+		}
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
 }
 
 type AccessTokenRequest struct {
@@ -180,6 +245,9 @@ const (
 	Production  EnvironmentType = "production"
 )
 
+// InitiateDeploymentGating is the command to initiate the deployment gating of a pending deployment.
+const InitiateDeploymentGating = "initiate_deployment_gating"
+
 type Command struct {
 	Command string `json:"command"`
 }
@@ -201,3 +269,125 @@ type RejectedDeployment struct {
 	Key    DeploymentKey `json:"key"`
 	Errors []Error       `json:"errors"`
 }
+
+type GatingStatus string
+
+const (
+	GatingAllowed   GatingStatus = "allowed"
+	GatingPrevented GatingStatus = "prevented"
+	GatingAwaiting  GatingStatus = "awaiting"
+	GatingInvalid   GatingStatus = "invalid"
+)
+
+type GatingStatusResponse struct {
+	DeploymentSequenceNumber int64                `json:"deploymentSequenceNumber"`
+	PipelineId               string               `json:"pipelineId"`
+	EnvironmentId            string               `json:"environmentId"`
+	UpdatedTimestamp         string               `json:"updatedTimestamp,omitempty"`
+	GatingStatus             GatingStatus         `json:"gatingStatus"`
+	Details                  []GatingStatusDetail `json:"details,omitempty"`
+}
+
+type GatingStatusDetail struct {
+	Type      string `json:"type"`
+	IssueKey  string `json:"issueKey,omitempty"`
+	IssueLink string `json:"issueLink,omitempty"`
+}
+
+type TransitionsResponse struct {
+	Transitions []Transition `json:"transitions"`
+}
+
+type Transition struct {
+	Id   string           `json:"id"`
+	Name string           `json:"name"`
+	To   TransitionStatus `json:"to"`
+}
+
+type TransitionStatus struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type TransitionRequest struct {
+	Transition TransitionId `json:"transition"`
+}
+
+type TransitionId struct {
+	Id string `json:"id"`
+}
+
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+type Version struct {
+	Id          string      `json:"id,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	ProjectId   json.Number `json:"projectId,omitempty"`
+	Released    bool        `json:"released,omitempty"`
+	ReleaseDate string      `json:"releaseDate,omitempty"`
+}
+
+type IssueUpdateRequest struct {
+	Update IssueUpdate `json:"update"`
+}
+
+type IssueUpdate struct {
+	FixVersions []VersionOperation `json:"fixVersions,omitempty"`
+}
+
+type VersionOperation struct {
+	Add *VersionName `json:"add,omitempty"`
+}
+
+type VersionName struct {
+	Name string `json:"name"`
+}
+
+type RemoteLink struct {
+	GlobalId     string                 `json:"globalId,omitempty"`
+	Application  *RemoteLinkApplication `json:"application,omitempty"`
+	Relationship string                 `json:"relationship,omitempty"`
+	Object       RemoteLinkObject       `json:"object"`
+}
+
+type RemoteLinkApplication struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type RemoteLinkObject struct {
+	Url     string            `json:"url"`
+	Title   string            `json:"title"`
+	Summary string            `json:"summary,omitempty"`
+	Icon    *RemoteLinkIcon   `json:"icon,omitempty"`
+	Status  *RemoteLinkStatus `json:"status,omitempty"`
+}
+
+type RemoteLinkIcon struct {
+	Url16x16 string `json:"url16x16,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Link     string `json:"link,omitempty"`
+}
+
+type RemoteLinkStatus struct {
+	Resolved bool            `json:"resolved"`
+	Icon     *RemoteLinkIcon `json:"icon,omitempty"`
+}
+
+// GetStateIcon returns the path of the Jira icon that represents the state, relative to the Jira base url.
+func GetStateIcon(state common.State) string {
+	switch state {
+	case common.Successful:
+		return "images/icons/emoticons/check.png"
+	case common.Failed:
+		return "images/icons/emoticons/error.png"
+	case common.Cancelled:
+		return "images/icons/emoticons/warning.png"
+	case common.InProgress, common.Pending:
+		return "images/icons/emoticons/information.png"
+	default:
+		return "images/icons/emoticons/help_16.png"
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/marvelution/ext-build-info/services/jira"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jiraSearchTestServer serves Jira searches for issue IN (...) queries using the REST api of the deployment type, and records the
+// searched batches and the maximum number of concurrent searches.
+type jiraSearchTestServer struct {
+	*httptest.Server
+	// The issues by the key they are found by, issues can be found by another key when they were moved.
+	issues   map[string]jira.Issue
+	pageSize int
+
+	lock          sync.Mutex
+	batches       [][]string
+	running       int
+	maxConcurrent int
+}
+
+func newJiraSearchTestServer(t *testing.T, deploymentType string, issues map[string]jira.Issue, pageSize int) *jiraSearchTestServer {
+	server := &jiraSearchTestServer{issues: issues, pageSize: pageSize}
+	restApi := "/rest/api/2/"
+	if deploymentType == jira.DeploymentTypeCloud {
+		restApi = "/rest/api/3/"
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/serverInfo" {
+			if err := json.NewEncoder(w).Encode(jira.ServerInfo{DeploymentType: deploymentType}); err != nil {
+				t.Error(err)
+			}
+			return
+		} else if r.URL.Path != restApi+"search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		request := &jira.SearchRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			t.Error(err)
+		}
+		keys := strings.Split(strings.TrimSuffix(strings.TrimPrefix(request.Jql, "issue IN ("), ")"), ",")
+		server.startSearch(keys, request.StartAt)
+		// Keep the search running, so concurrent searches overlap.
+		time.Sleep(20 * time.Millisecond)
+		defer server.endSearch()
+
+		// Issues are returned in reverse order of the keys, like sorting by created date would.
+		var found []jira.Issue
+		for i := len(keys) - 1; i >= 0; i-- {
+			if issue, ok := server.issues[keys[i]]; ok {
+				found = append(found, issue)
+			}
+		}
+		result := &jira.SearchResult{StartAt: request.StartAt, Total: len(found)}
+		end := request.StartAt + server.pageSize
+		if end > len(found) {
+			end = len(found)
+		}
+		result.Issues = found[request.StartAt:end]
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *jiraSearchTestServer) startSearch(keys []string, startAt int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if startAt == 0 {
+		s.batches = append(s.batches, keys)
+	}
+	s.running++
+	if s.running > s.maxConcurrent {
+		s.maxConcurrent = s.running
+	}
+}
+
+func (s *jiraSearchTestServer) endSearch() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.running--
+}
+
+func TestJiraGetIssues(t *testing.T) {
+	for _, deploymentType := range []string{jira.DeploymentTypeCloud, jira.DeploymentTypeServer} {
+		t.Run(deploymentType, func(t *testing.T) {
+			testJiraGetIssues(t, deploymentType)
+		})
+	}
+}
+
+func testJiraGetIssues(t *testing.T, deploymentType string) {
+	var keys []string
+	issues := map[string]jira.Issue{}
+	for i := 1; i <= 230; i++ {
+		key := fmt.Sprintf("ABC-%d", i)
+		keys = append(keys, key)
+		if i%10 != 0 {
+			issues[key] = jira.Issue{Key: key, Fields: jira.IssueFields{Summary: "Issue " + key}}
+		}
+	}
+	// ABC-3 was moved to another project.
+	issues["ABC-3"] = jira.Issue{Key: "XYZ-3", Fields: jira.IssueFields{Summary: "Moved issue"}}
+	server := newJiraSearchTestServer(t, deploymentType, issues, 20)
+	service, err := NewJiraService(server.URL, "user", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := service.GetIssues(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []buildinfo.AffectedIssue
+	for _, key := range keys {
+		if issue, ok := issues[key]; ok && issue.Key == key {
+			expected = append(expected, buildinfo.AffectedIssue{Key: key, Summary: "Issue " + key, Url: server.URL + "/browse/" + key})
+		}
+	}
+	expected = append(expected, buildinfo.AffectedIssue{Key: "XYZ-3", Summary: "Moved issue", Url: server.URL + "/browse/XYZ-3"})
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("GetIssues() = %v, want %v", found, expected)
+	}
+
+	if len(server.batches) != 5 {
+		t.Errorf("GetIssues() searched %d batches, want 5", len(server.batches))
+	}
+	searched := 0
+	for _, batch := range server.batches {
+		if len(batch) > JiraIssueBatchSize {
+			t.Errorf("GetIssues() searched a batch of %d keys, want at most %d", len(batch), JiraIssueBatchSize)
+		}
+		searched += len(batch)
+	}
+	if searched != len(keys) {
+		t.Errorf("GetIssues() searched %d keys, want %d", searched, len(keys))
+	}
+	if server.maxConcurrent > JiraSearchConcurrency || server.maxConcurrent < 2 {
+		t.Errorf("GetIssues() ran %d concurrent searches, want between 2 and %d", server.maxConcurrent, JiraSearchConcurrency)
+	}
+}
+
+func TestJiraGetIssuesWithoutKeys(t *testing.T) {
+	service, err := NewJiraService("https://jira.example.com", "user", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := service.GetIssues(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || len(found) != 0 {
+		t.Errorf("GetIssues() = %v, want no issues", found)
+	}
+}
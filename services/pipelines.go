@@ -148,7 +148,7 @@ func (ps *PipelinesService) GetRun(runId int64) (*pipelines.Run, error) {
 	if len(*runs) == 1 {
 		return &(*runs)[0], nil
 	} else {
-		return nil, errorutils.CheckErrorf(fmt.Sprintf("No pipeline run found with id %s\n", runId))
+		return nil, errorutils.CheckErrorf(fmt.Sprintf("No pipeline run found with id %d\n", runId))
 	}
 }
 
@@ -180,7 +180,7 @@ func (ps *PipelinesService) GetRunResourceVersion(versionId string) (*pipelines.
 	if len(*versions) == 1 {
 		return &(*versions)[0], nil
 	} else {
-		return nil, errorutils.CheckErrorf(fmt.Sprintf("No pipeline run resource version found with id %d\n", versionId))
+		return nil, errorutils.CheckErrorf(fmt.Sprintf("No pipeline run resource version found with id %s\n", versionId))
 	}
 }
 
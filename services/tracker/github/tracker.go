@@ -0,0 +1,73 @@
+package github
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/tracker"
+	"github.com/marvelution/ext-build-info/util"
+)
+
+const (
+	Name = "GITHUB"
+	// IssueRegex matches issue references like #123 and owner/repo#123
+	IssueRegex = "(?:^|[^\\w/.-])((?:[\\w.-]+/[\\w.-]+)?#\\p{N}+)\\b"
+)
+
+func init() {
+	tracker.Register(Name, NewTracker)
+}
+
+type Tracker struct {
+	details tracker.Details
+}
+
+func NewTracker(details tracker.Details) tracker.Tracker {
+	return &Tracker{details: details}
+}
+
+func (t *Tracker) Name() string {
+	return Name
+}
+
+func (t *Tracker) DefaultRegexp() (string, int) {
+	return IssueRegex, 1
+}
+
+// NormalizeKey qualifies references without a repository, like #123, with the repository of the vcs.
+func (t *Tracker) NormalizeKey(vcs buildinfo.Vcs, key string) string {
+	repository, number := services.SplitIssueReference(key, "#")
+	if repository == "" {
+		repository = util.GetVcsRepositoryPath(vcs.Url)
+		if repository == "" {
+			log.Debug("Unable to determine the GitHub repository of issue ", key)
+			return ""
+		}
+	}
+	return repository + "#" + number
+}
+
+func (t *Tracker) Resolve(keys []string) ([]buildinfo.AffectedIssue, error) {
+	client, err := services.NewGitHubService(t.details.Url, t.details.Token)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetIssues(keys)
+}
+
+func (t *Tracker) ProjectKey(key string) string {
+	repository, _ := services.SplitIssueReference(key, "#")
+	return repository
+}
+
+func (t *Tracker) Validate() error {
+	t.details.LoadIntegration()
+	if t.details.Url == "" {
+		t.details.Url = services.GitHubApiUrl
+	}
+	if t.details.Token == "" {
+		return errorutils.CheckErrorf("Missing GitHub details")
+	}
+	return nil
+}
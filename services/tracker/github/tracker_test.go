@@ -0,0 +1,48 @@
+package github
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/marvelution/ext-build-info/services/tracker"
+	"testing"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		vcsUrl string
+		key    string
+		want   string
+	}{
+		{"qualified with the vcs repository", "https://github.com/owner/repo.git", "#1", "owner/repo#1"},
+		{"qualified with the ssh vcs repository", "git@github.com:owner/repo.git", "#1", "owner/repo#1"},
+		{"other repository", "https://github.com/owner/repo.git", "owner/other#2", "owner/other#2"},
+		{"unknown repository", "", "#1", ""},
+		{"other repository without vcs", "", "owner/other#2", "owner/other#2"},
+	}
+	githubTracker := NewTracker(tracker.Details{Name: Name})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if key := githubTracker.NormalizeKey(buildinfo.Vcs{Url: test.vcsUrl}, test.key); key != test.want {
+				t.Errorf("NormalizeKey(%s, %s) = %s, want %s", test.vcsUrl, test.key, key, test.want)
+			}
+		})
+	}
+}
+
+func TestProjectKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		project string
+	}{
+		{"owner/repo#1", "owner/repo"},
+		{"#1", ""},
+	}
+	githubTracker := &Tracker{}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			if project := githubTracker.ProjectKey(test.key); project != test.project {
+				t.Errorf("ProjectKey(%s) = %s, want %s", test.key, project, test.project)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package gitlab
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/tracker"
+	"github.com/marvelution/ext-build-info/util"
+)
+
+const (
+	Name = "GITLAB"
+	// IssueRegex matches issue references like #12 and group/project#12, and merge request references like !34
+	IssueRegex = "(?:^|[^\\w/.-])((?:[\\w.-]+(?:/[\\w.-]+)+)?[#!]\\p{N}+)\\b"
+)
+
+func init() {
+	tracker.Register(Name, NewTracker)
+}
+
+type Tracker struct {
+	details tracker.Details
+}
+
+func NewTracker(details tracker.Details) tracker.Tracker {
+	return &Tracker{details: details}
+}
+
+func (t *Tracker) Name() string {
+	return Name
+}
+
+func (t *Tracker) DefaultRegexp() (string, int) {
+	return IssueRegex, 1
+}
+
+// NormalizeKey qualifies references without a project, like #12 and !34, with the project of the vcs.
+func (t *Tracker) NormalizeKey(vcs buildinfo.Vcs, key string) string {
+	project, kind, iid := services.SplitGitLabReference(key)
+	if iid == "" {
+		return ""
+	}
+	if project == "" {
+		project = util.GetVcsRepositoryPath(vcs.Url)
+		if project == "" {
+			log.Debug("Unable to determine the GitLab project of reference ", key)
+			return ""
+		}
+	}
+	return project + kind + iid
+}
+
+func (t *Tracker) Resolve(keys []string) ([]buildinfo.AffectedIssue, error) {
+	client, err := services.NewGitLabService(t.details.Url, t.details.Token)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetIssues(keys)
+}
+
+func (t *Tracker) ProjectKey(key string) string {
+	project, _, _ := services.SplitGitLabReference(key)
+	return project
+}
+
+func (t *Tracker) Validate() error {
+	t.details.LoadIntegration()
+	if t.details.Url == "" {
+		t.details.Url = services.GitLabUrl
+	}
+	if t.details.Token == "" {
+		return errorutils.CheckErrorf("Missing GitLab details")
+	}
+	return nil
+}
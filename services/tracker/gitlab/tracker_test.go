@@ -0,0 +1,49 @@
+package gitlab
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/marvelution/ext-build-info/services/tracker"
+	"testing"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		vcsUrl string
+		key    string
+		want   string
+	}{
+		{"issue qualified with the vcs project", "https://gitlab.com/group/project.git", "#1", "group/project#1"},
+		{"merge request qualified with the vcs project", "git@gitlab.com:group/sub/project.git", "!2", "group/sub/project!2"},
+		{"other project", "https://gitlab.com/group/project.git", "group/other#3", "group/other#3"},
+		{"unknown project", "", "#1", ""},
+		{"invalid reference", "https://gitlab.com/group/project.git", "group/project", ""},
+	}
+	gitlabTracker := NewTracker(tracker.Details{Name: Name})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if key := gitlabTracker.NormalizeKey(buildinfo.Vcs{Url: test.vcsUrl}, test.key); key != test.want {
+				t.Errorf("NormalizeKey(%s, %s) = %s, want %s", test.vcsUrl, test.key, key, test.want)
+			}
+		})
+	}
+}
+
+func TestProjectKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		project string
+	}{
+		{"group/sub/project#1", "group/sub/project"},
+		{"group/project!2", "group/project"},
+		{"group/project", ""},
+	}
+	gitlabTracker := &Tracker{}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			if project := gitlabTracker.ProjectKey(test.key); project != test.project {
+				t.Errorf("ProjectKey(%s) = %s, want %s", test.key, project, test.project)
+			}
+		})
+	}
+}
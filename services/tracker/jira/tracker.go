@@ -0,0 +1,107 @@
+package jira
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/marvelution/ext-build-info/services"
+	"github.com/marvelution/ext-build-info/services/tracker"
+	"strings"
+)
+
+const (
+	Name          = "JIRA"
+	IssueKeyRegex = "(((?:\\p{Lu}[\\p{Lu}\\p{N}_]+|\\p{Ll}[\\p{Ll}\\p{N}_]+))-\\p{N}+)"
+)
+
+// fieldIds maps field names that differ from the Jira field ids.
+var fieldIds = map[string]string{
+	"type": "issuetype",
+}
+
+func init() {
+	tracker.Register(Name, NewTracker)
+}
+
+type Tracker struct {
+	details tracker.Details
+}
+
+func NewTracker(details tracker.Details) tracker.Tracker {
+	return &Tracker{details: details}
+}
+
+func (t *Tracker) Name() string {
+	return Name
+}
+
+func (t *Tracker) DefaultRegexp() (string, int) {
+	return IssueKeyRegex, 1
+}
+
+func (t *Tracker) NormalizeKey(_ buildinfo.Vcs, key string) string {
+	return strings.ToUpper(key)
+}
+
+func (t *Tracker) Resolve(keys []string) ([]buildinfo.AffectedIssue, error) {
+	client, err := services.NewJiraService(t.details.Url, t.details.Username, t.details.Token)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetIssues(keys)
+}
+
+func (t *Tracker) ResolveFields(keys []string, fields []string) ([]buildinfo.AffectedIssue, map[string]map[string]string, error) {
+	client, err := services.NewJiraService(t.details.Url, t.details.Username, t.details.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ids []string
+	for _, field := range fields {
+		if id, found := fieldIds[field]; found {
+			ids = append(ids, id)
+		} else {
+			ids = append(ids, field)
+		}
+	}
+	issues, idValues, err := client.GetIssuesWithFields(keys, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	fieldValues := map[string]map[string]string{}
+	for key, values := range idValues {
+		fieldValues[key] = map[string]string{}
+		for i, field := range fields {
+			fieldValues[key][field] = values[ids[i]]
+		}
+	}
+	return issues, fieldValues, nil
+}
+
+func (t *Tracker) ProjectKey(key string) string {
+	return ProjectKey(key)
+}
+
+// ProjectKey returns the key of the project of the Jira issue key, like ABC for ABC-1, or an empty string if the key is invalid.
+func ProjectKey(key string) string {
+	if index := strings.LastIndex(key, "-"); index > 0 {
+		return key[:index]
+	}
+	return ""
+}
+
+func (t *Tracker) DiscoverProjects() ([]string, error) {
+	client, err := services.NewJiraService(t.details.Url, t.details.Username, t.details.Token)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetProjectKeys()
+}
+
+func (t *Tracker) Validate() error {
+	t.details.LoadIntegration()
+	// The username is optional, as Jira Server and Data Center also support personal access tokens.
+	if t.details.Url == "" || t.details.Token == "" {
+		return errorutils.CheckErrorf("Missing Jira details")
+	}
+	return nil
+}
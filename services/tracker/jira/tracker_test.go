@@ -0,0 +1,46 @@
+package jira
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/marvelution/ext-build-info/services/tracker"
+	"testing"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"ABC-1", "ABC-1"},
+		{"abc-1", "ABC-1"},
+		{"Abc_2-10", "ABC_2-10"},
+	}
+	jiraTracker := NewTracker(tracker.Details{Name: Name})
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			if key := jiraTracker.NormalizeKey(buildinfo.Vcs{}, test.key); key != test.want {
+				t.Errorf("NormalizeKey(%s) = %s, want %s", test.key, key, test.want)
+			}
+		})
+	}
+}
+
+func TestProjectKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		project string
+	}{
+		{"ABC-1", "ABC"},
+		{"ABC_2-10", "ABC_2"},
+		{"ABC", ""},
+		{"-1", ""},
+	}
+	jiraTracker := &Tracker{}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			if project := jiraTracker.ProjectKey(test.key); project != test.project {
+				t.Errorf("ProjectKey(%s) = %s, want %s", test.key, project, test.project)
+			}
+		})
+	}
+}
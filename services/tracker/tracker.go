@@ -0,0 +1,99 @@
+package tracker
+
+import (
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tracker resolves issue keys collected from git into affected issues.
+type Tracker interface {
+	// Name returns the tracker name that is stored in the build-info.
+	Name() string
+	// DefaultRegexp returns the default regular expression and the capturing group index of the issue key.
+	DefaultRegexp() (string, int)
+	// NormalizeKey normalizes an issue key found in the given vcs, an empty key is returned if the key can't be normalized.
+	NormalizeKey(vcs buildinfo.Vcs, key string) string
+	// Resolve resolves the normalized issue keys into affected issues, unknown issues are left out.
+	Resolve(keys []string) ([]buildinfo.AffectedIssue, error)
+	// Validate validates that the tracker details and credentials are complete.
+	Validate() error
+}
+
+// ProjectResolver is implemented by trackers that can tell the project an issue belongs to from its key.
+type ProjectResolver interface {
+	// ProjectKey returns the key of the project the issue with the normalized key belongs to.
+	ProjectKey(key string) string
+}
+
+// ProjectDiscoverer is implemented by trackers that can list the keys of the projects issues can belong to.
+type ProjectDiscoverer interface {
+	// DiscoverProjects returns the keys of all projects that are accessible with the tracker credentials.
+	DiscoverProjects() ([]string, error)
+}
+
+// FieldResolver is implemented by trackers that can resolve extra fields of issues, like the status or assignee.
+type FieldResolver interface {
+	// ResolveFields resolves the normalized issue keys into affected issues, and the values of the fields of each issue by key.
+	ResolveFields(keys []string, fields []string) ([]buildinfo.AffectedIssue, map[string]map[string]string, error)
+}
+
+// Details holds the details a tracker is created with, the name is also the name of the integration to load details from.
+type Details struct {
+	Name     string
+	Url      string
+	Username string
+	Token    string
+}
+
+// LoadIntegration loads the url, username and token from the integration environment variables, if no url is set.
+func (d *Details) LoadIntegration() {
+	if d.Url == "" {
+		log.Debug("Loading tracker details from integration ", d.Name)
+		d.Url = os.Getenv("int_" + d.Name + "_url")
+		d.Username = os.Getenv("int_" + d.Name + "_username")
+		d.Token = os.Getenv("int_" + d.Name + "_token")
+	}
+}
+
+type Factory func(details Details) Tracker
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]Factory{}
+)
+
+// Register registers a tracker factory by name, names are case-insensitive.
+func Register(name string, factory Factory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[strings.ToUpper(name)] = factory
+}
+
+// Names returns the names of all registered trackers.
+func Names() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the tracker registered with the name in the details.
+func New(details Details) (Tracker, error) {
+	registryLock.RLock()
+	factory, found := registry[strings.ToUpper(details.Name)]
+	registryLock.RUnlock()
+	if !found {
+		return nil, errorutils.CheckErrorf("Unsupported tracker: %s, supported trackers are: %s", details.Name,
+			strings.Join(Names(), ", "))
+	}
+	return factory(details), nil
+}
@@ -0,0 +1,526 @@
+package util
+
+import (
+	"container/heap"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	buildinfo "github.com/jfrog/build-info-go/entities"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MaxVisitedCommits is the maximum number of commits that are read from the git log, including the commits that are left out
+// because they don't change the paths of the path filter.
+const MaxVisitedCommits = 1000
+
+// RevisionRangeError to be thrown when revision could not be found in the git revision range.
+type RevisionRangeError struct {
+	ErrorMsg string
+}
+
+func (err RevisionRangeError) Error() string {
+	return err.ErrorMsg
+}
+
+// GitRepository reads vcs details and history of a git repository without requiring git to be installed.
+type GitRepository struct {
+	path       string
+	repository *git.Repository
+	submodule  bool
+}
+
+// OpenGitRepository opens the git repository at, or in one of the parents of, the given path.
+func OpenGitRepository(path string) (*GitRepository, error) {
+	repository, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, errorutils.CheckErrorf("Failed to open git repository at %s: %s", path, err.Error())
+	}
+	return &GitRepository{path: path, repository: repository}, nil
+}
+
+// GetPath returns the root directory of the working tree, or the given path for bare repositories.
+func (gr *GitRepository) GetPath() string {
+	if worktree, err := gr.repository.Worktree(); err == nil {
+		return worktree.Filesystem.Root()
+	}
+	return gr.path
+}
+
+// IsSubmodule returns true if the repository was opened as a submodule of another repository.
+func (gr *GitRepository) IsSubmodule() bool {
+	return gr.submodule
+}
+
+// GetSubmodules returns the initialized submodules of the repository, including the nested submodules.
+// Submodules that are not checked out are skipped.
+func (gr *GitRepository) GetSubmodules() ([]*GitRepository, error) {
+	worktree, err := gr.repository.Worktree()
+	if err == git.ErrIsBareRepository {
+		return nil, nil
+	} else if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+
+	var repositories []*GitRepository
+	for _, submodule := range submodules {
+		path := filepath.Join(worktree.Filesystem.Root(), submodule.Config().Path)
+		repository, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: false, EnableDotGitCommonDir: true})
+		if err != nil {
+			log.Warn("Skipping submodule " + submodule.Config().Name + " at " + path + ": " + err.Error())
+			continue
+		}
+		log.Debug("Found submodule " + submodule.Config().Name + " at " + path)
+		submoduleRepository := &GitRepository{path: path, repository: repository, submodule: true}
+		repositories = append(repositories, submoduleRepository)
+
+		nested, err := submoduleRepository.GetSubmodules()
+		if err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, nested...)
+	}
+	return repositories, nil
+}
+
+// GetVcs returns the remote url, revision, branch and message of the current HEAD.
+func (gr *GitRepository) GetVcs() (buildinfo.Vcs, error) {
+	vcs := buildinfo.Vcs{Url: gr.getRemoteUrl()}
+
+	head, err := gr.repository.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		log.Debug("No HEAD was found. Assuming git repository is empty")
+		return vcs, nil
+	} else if err != nil {
+		return vcs, errorutils.CheckError(err)
+	}
+	vcs.Revision = head.Hash().String()
+	if head.Name().IsBranch() {
+		vcs.Branch = head.Name().Short()
+	}
+
+	commit, err := gr.repository.CommitObject(head.Hash())
+	if err != nil {
+		log.Debug("Latest commit message was not extracted due to", err.Error())
+	} else {
+		vcs.Message = strings.TrimSpace(commit.Message)
+	}
+	return vcs, nil
+}
+
+func (gr *GitRepository) getRemoteUrl() string {
+	remote, err := gr.repository.Remote(git.DefaultRemoteName)
+	if err != nil || len(remote.Config().URLs) == 0 {
+		log.Debug("No origin remote url was found for git repository", gr.path)
+		return ""
+	}
+	originUrl := remote.Config().URLs[0]
+	if !strings.HasSuffix(originUrl, ".git") {
+		originUrl += ".git"
+	}
+
+	// Mask url if required
+	regExp, err := clientutils.GetRegExp(clientutils.CredentialsInUrlRegexp)
+	if err != nil {
+		return originUrl
+	}
+	if matchedResult := regExp.FindString(originUrl); matchedResult != "" {
+		return clientutils.RemoveCredentials(originUrl, matchedResult)
+	}
+	return originUrl
+}
+
+// PathFilter selects commits by the paths they change. Paths are relative to the root of the repository, and may either be a
+// directory, a file or a glob pattern.
+type PathFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// IsEmpty returns true if the filter doesn't have any include or exclude paths.
+func (pf *PathFilter) IsEmpty() bool {
+	return pf == nil || (len(pf.Include) == 0 && len(pf.Exclude) == 0)
+}
+
+// Matches returns true if the path is matched by one of the include paths, if any, and none of the exclude paths.
+func (pf *PathFilter) Matches(path string) bool {
+	for _, pattern := range pf.Exclude {
+		if matchesPath(pattern, path) {
+			return false
+		}
+	}
+	if len(pf.Include) == 0 {
+		return true
+	}
+	for _, pattern := range pf.Include {
+		if matchesPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPath(pattern, path string) bool {
+	pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+	if pattern == "" || path == pattern || strings.HasPrefix(path, pattern+"/") {
+		return true
+	}
+	matched, _ := filepath.Match(pattern, path)
+	return matched
+}
+
+// HeadMatches returns true if the commit of HEAD changes any path matched by the filter.
+func (gr *GitRepository) HeadMatches(filter *PathFilter) (bool, error) {
+	if filter.IsEmpty() {
+		return true, nil
+	}
+	headCommit, err := gr.getHeadCommit()
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	return gr.commitMatches(headCommit, filter)
+}
+
+// Returns true if the commit changes any path matched by the filter, compared to its first parent.
+func (gr *GitRepository) commitMatches(commit *object.Commit, filter *PathFilter) (bool, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		// The parent of a shallow commit is not available, in which case all files are considered changed.
+		if parent, err := commit.Parent(0); err == nil {
+			if parentTree, err = parent.Tree(); err != nil {
+				return false, errorutils.CheckError(err)
+			}
+		}
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	for _, change := range changes {
+		if (change.From.Name != "" && filter.Matches(change.From.Name)) || (change.To.Name != "" && filter.Matches(change.To.Name)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GitCommit holds the details of a commit in the git log.
+type GitCommit struct {
+	Hash     string
+	RefNames []string
+	Message  string
+}
+
+// GetSubject returns the first line of the commit message.
+func (gc *GitCommit) GetSubject() string {
+	return strings.SplitN(strings.TrimSpace(gc.Message), "\n", 2)[0]
+}
+
+// GetLogLine returns the ref names pointing to the commit followed by the commit subject, like git log --pretty=format:%d%s.
+func (gc *GitCommit) GetLogLine() string {
+	if len(gc.RefNames) > 0 {
+		return " (" + strings.Join(gc.RefNames, ", ") + ")" + gc.GetSubject()
+	}
+	return gc.GetSubject()
+}
+
+// Trailer is a token and value pair from the trailer block of a commit message, like Fixes: ABC-1.
+type Trailer struct {
+	Token string
+	Value string
+}
+
+var (
+	paragraphRegexp = regexp.MustCompile(`\n\s*\n`)
+	trailerRegexp   = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)\s*:\s*(.*)$`)
+)
+
+// GetTrailers returns the trailers of the commit message. Like git interpret-trailers, the trailers are taken from the last
+// paragraph of the message, provided it isn't the subject and all its lines are trailers or continuation lines.
+func (gc *GitCommit) GetTrailers() []Trailer {
+	paragraphs := paragraphRegexp.Split(strings.TrimSpace(gc.Message), -1)
+	if len(paragraphs) < 2 {
+		return nil
+	}
+	var trailers []Trailer
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		if len(trailers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			trailers[len(trailers)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		matches := trailerRegexp.FindStringSubmatch(strings.TrimRight(line, " \t\r"))
+		if matches == nil {
+			return nil
+		}
+		trailers = append(trailers, Trailer{Token: matches[1], Value: matches[2]})
+	}
+	return trailers
+}
+
+// GetCommits returns up to limit commits in the lastRevision..HEAD range, starting from the latest commit.
+// Only commits that change paths matched by the filter are included, like git log -- <paths>, if a filter is given. At most
+// MaxVisitedCommits commits are read.
+// A RevisionRangeError is returned if the lastRevision is not in the history of the repository.
+func (gr *GitRepository) GetCommits(lastRevision string, limit int, filter *PathFilter) ([]GitCommit, error) {
+	headCommit, err := gr.getHeadCommit()
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+
+	boundary, err := gr.getShallowBoundary()
+	if err != nil {
+		return nil, err
+	}
+	walk := gr.newCommitWalk(boundary)
+	walk.push(headCommit, false)
+	if lastRevision != "" {
+		lastCommit, err := gr.resolveCommit(lastRevision)
+		if err != nil {
+			return nil, RevisionRangeError{ErrorMsg: "Revision: '" + lastRevision + "' that was fetched from latest build info " +
+				"does not exist in the git revision range. No new issues are added."}
+		}
+		// Exclude the commits reachable from the last revision, like git log <lastRevision>..
+		walk.push(lastCommit, true)
+	}
+
+	refNames, err := gr.getRefNames()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("Reading git log: ", lastRevision+"..HEAD", "limit", limit)
+	var commits []GitCommit
+	for visited := 0; len(commits) < limit; visited++ {
+		if visited >= MaxVisitedCommits {
+			log.Info("Stopped reading the git log after", MaxVisitedCommits, "commits")
+			break
+		}
+		commit, err := walk.next()
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		if commit == nil {
+			break
+		}
+		if !filter.IsEmpty() {
+			if matches, err := gr.commitMatches(commit, filter); err != nil {
+				return nil, err
+			} else if !matches {
+				continue
+			}
+		}
+		commits = append(commits, GitCommit{Hash: commit.Hash.String(), RefNames: refNames[commit.Hash], Message: commit.Message})
+	}
+	return commits, nil
+}
+
+// commitWalk walks the history of HEAD in committer time order, leaving out the history of excluded commits. Both histories
+// are walked together, like git rev-list does, so the history of excluded commits is only read as far as it overlaps.
+type commitWalk struct {
+	repository *git.Repository
+	queue      commitQueue
+	seen       map[plumbing.Hash]bool
+	excluded   map[plumbing.Hash]bool
+}
+
+func (gr *GitRepository) newCommitWalk(boundary map[plumbing.Hash]bool) *commitWalk {
+	return &commitWalk{repository: gr.repository, seen: boundary, excluded: map[plumbing.Hash]bool{}}
+}
+
+func (cw *commitWalk) push(commit *object.Commit, excluded bool) {
+	if excluded {
+		cw.excluded[commit.Hash] = true
+	}
+	heap.Push(&cw.queue, commit)
+}
+
+// Returns the next commit that isn't excluded, or nil when only excluded commits are left.
+func (cw *commitWalk) next() (*object.Commit, error) {
+	for cw.hasIncluded() {
+		commit := heap.Pop(&cw.queue).(*object.Commit)
+		if cw.seen[commit.Hash] {
+			continue
+		}
+		cw.seen[commit.Hash] = true
+		excluded := cw.excluded[commit.Hash]
+		for _, hash := range commit.ParentHashes {
+			if excluded {
+				cw.excluded[hash] = true
+			}
+			if cw.seen[hash] {
+				continue
+			}
+			parent, err := cw.repository.CommitObject(hash)
+			if err != nil {
+				return nil, err
+			}
+			heap.Push(&cw.queue, parent)
+		}
+		if !excluded {
+			return commit, nil
+		}
+	}
+	return nil, nil
+}
+
+// Returns true if the queue has commits that aren't excluded, otherwise the rest of the history is excluded.
+func (cw *commitWalk) hasIncluded() bool {
+	for _, commit := range cw.queue {
+		if !cw.excluded[commit.Hash] {
+			return true
+		}
+	}
+	return false
+}
+
+// commitQueue is a priority queue of commits, ordered by committer time with the latest commit first.
+type commitQueue []*object.Commit
+
+func (cq commitQueue) Len() int { return len(cq) }
+
+func (cq commitQueue) Less(i, j int) bool { return cq[i].Committer.When.After(cq[j].Committer.When) }
+
+func (cq commitQueue) Swap(i, j int) { cq[i], cq[j] = cq[j], cq[i] }
+
+func (cq *commitQueue) Push(commit any) { *cq = append(*cq, commit.(*object.Commit)) }
+
+func (cq *commitQueue) Pop() any {
+	old := *cq
+	commit := old[len(old)-1]
+	*cq = old[:len(old)-1]
+	return commit
+}
+
+// IsAncestorOfHead returns true if the revision exists and is the current HEAD, or one of its ancestors.
+func (gr *GitRepository) IsAncestorOfHead(revision string) bool {
+	commit, err := gr.resolveCommit(revision)
+	if err != nil {
+		return false
+	}
+	headCommit, err := gr.getHeadCommit()
+	if err != nil {
+		return false
+	}
+	excluded, err := gr.getShallowBoundary()
+	if err != nil {
+		return false
+	}
+	found := false
+	err = object.NewCommitIterCTime(headCommit, excluded, nil).ForEach(func(ancestor *object.Commit) error {
+		if ancestor.Hash == commit.Hash {
+			found = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debug("Failed to read the history of HEAD:", err.Error())
+	}
+	return found
+}
+
+// GetMergeBase returns the best common ancestor of the revision and the current HEAD, like git merge-base <revision> HEAD.
+// An empty string is returned if the revision doesn't exist or there is no common ancestor.
+func (gr *GitRepository) GetMergeBase(revision string) string {
+	commit, err := gr.resolveCommit(revision)
+	if err != nil {
+		return ""
+	}
+	headCommit, err := gr.getHeadCommit()
+	if err != nil {
+		return ""
+	}
+	mergeBases, err := commit.MergeBase(headCommit)
+	if err != nil {
+		log.Debug("Failed to determine the merge-base of "+revision+" and HEAD:", err.Error())
+		return ""
+	}
+	if len(mergeBases) == 0 {
+		return ""
+	}
+	return mergeBases[0].Hash.String()
+}
+
+func (gr *GitRepository) getHeadCommit() (*object.Commit, error) {
+	head, err := gr.repository.Head()
+	if err != nil {
+		return nil, err
+	}
+	return gr.repository.CommitObject(head.Hash())
+}
+
+func (gr *GitRepository) resolveCommit(revision string) (*object.Commit, error) {
+	hash, err := gr.repository.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, err
+	}
+	return gr.repository.CommitObject(*hash)
+}
+
+// Returns the parents of shallow commits, these are not available and should not be traversed.
+func (gr *GitRepository) getShallowBoundary() (map[plumbing.Hash]bool, error) {
+	boundary := map[plumbing.Hash]bool{}
+	shallowCommits, err := gr.repository.Storer.Shallow()
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	for _, hash := range shallowCommits {
+		commit, err := gr.repository.CommitObject(hash)
+		if err != nil {
+			continue
+		}
+		for _, parent := range commit.ParentHashes {
+			boundary[parent] = true
+		}
+	}
+	return boundary, nil
+}
+
+// Returns the ref names pointing to each commit, like git log %d decorations.
+func (gr *GitRepository) getRefNames() (map[plumbing.Hash][]string, error) {
+	refNames := map[plumbing.Hash][]string{}
+	head, err := gr.repository.Head()
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if head.Name().IsBranch() {
+		refNames[head.Hash()] = append(refNames[head.Hash()], "HEAD -> "+head.Name().Short())
+	} else {
+		refNames[head.Hash()] = append(refNames[head.Hash()], "HEAD")
+	}
+
+	references, err := gr.repository.References()
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	err = references.ForEach(func(reference *plumbing.Reference) error {
+		if reference.Type() != plumbing.HashReference {
+			return nil
+		}
+		hash := reference.Hash()
+		name := reference.Name()
+		if name.IsTag() {
+			if tag, err := gr.repository.TagObject(hash); err == nil {
+				hash = tag.Target
+			}
+			refNames[hash] = append(refNames[hash], "tag: "+name.Short())
+		} else if name.IsRemote() || (name.IsBranch() && name != head.Name()) {
+			refNames[hash] = append(refNames[hash], name.Short())
+		}
+		return nil
+	})
+	return refNames, errorutils.CheckError(err)
+}
@@ -0,0 +1,248 @@
+package util
+
+import (
+	"errors"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetTrailers(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		trailers []Trailer
+	}{
+		{"subject only", "Fixes: ABC-1", nil},
+		{"no trailers", "Add feature\n\nThis adds the feature.", nil},
+		{"trailers", "Add feature\n\nThis adds the feature.\n\nFixes: ABC-1\nRefs: ABC-2",
+			[]Trailer{{Token: "Fixes", Value: "ABC-1"}, {Token: "Refs", Value: "ABC-2"}}},
+		{"trailers without body", "Add feature\n\nSigned-off-by: John Doe <john@example.com>",
+			[]Trailer{{Token: "Signed-off-by", Value: "John Doe <john@example.com>"}}},
+		{"continuation line", "Add feature\n\nFixes: ABC-1,\n  ABC-2\nRefs: ABC-3",
+			[]Trailer{{Token: "Fixes", Value: "ABC-1, ABC-2"}, {Token: "Refs", Value: "ABC-3"}}},
+		{"trailing whitespace", "Add feature\n\nFixes : ABC-1  \n\n",
+			[]Trailer{{Token: "Fixes", Value: "ABC-1"}}},
+		{"windows line endings", "Add feature\r\n\r\nFixes: ABC-1\r\nRefs: ABC-2\r\n",
+			[]Trailer{{Token: "Fixes", Value: "ABC-1"}, {Token: "Refs", Value: "ABC-2"}}},
+		{"last paragraph is not only trailers", "Add feature\n\nFixes: ABC-1\nThis is not a trailer.", nil},
+		{"trailers not in last paragraph", "Add feature\n\nFixes: ABC-1\n\nThis adds the feature.", nil},
+		{"continuation line without trailer", "Add feature\n\n  ABC-1\nFixes: ABC-2", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commit := GitCommit{Message: test.message}
+			if trailers := commit.GetTrailers(); !reflect.DeepEqual(trailers, test.trailers) {
+				t.Errorf("GetTrailers() = %v, want %v", trailers, test.trailers)
+			}
+		})
+	}
+}
+
+// testRepository creates commits in a git repository in a temporary directory, one minute apart.
+type testRepository struct {
+	t          *testing.T
+	repository *git.Repository
+	worktree   *git.Worktree
+	when       time.Time
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	repository, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testRepository{t: t, repository: repository, worktree: worktree, when: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (tr *testRepository) getGitRepository() *GitRepository {
+	return &GitRepository{path: tr.worktree.Filesystem.Root(), repository: tr.repository}
+}
+
+// Commits the change of the paths, the message is written to the paths. The commit is a merge commit if parents are given.
+func (tr *testRepository) commit(message string, paths []string, parents ...plumbing.Hash) plumbing.Hash {
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Join(tr.worktree.Filesystem.Root(), filepath.Dir(path)), 0755); err != nil {
+			tr.t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tr.worktree.Filesystem.Root(), path), []byte(message), 0644); err != nil {
+			tr.t.Fatal(err)
+		}
+		if _, err := tr.worktree.Add(path); err != nil {
+			tr.t.Fatal(err)
+		}
+	}
+	tr.when = tr.when.Add(time.Minute)
+	signature := &object.Signature{Name: "Test", Email: "test@example.com", When: tr.when}
+	hash, err := tr.worktree.Commit(message, &git.CommitOptions{Author: signature, Parents: parents, AllowEmptyCommits: true})
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+	return hash
+}
+
+func (tr *testRepository) checkout(branch string, create bool) {
+	err := tr.worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: create})
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+func getSubjects(commits []GitCommit) []string {
+	subjects := []string{}
+	for _, commit := range commits {
+		subjects = append(subjects, commit.GetSubject())
+	}
+	return subjects
+}
+
+func TestGetCommits(t *testing.T) {
+	tr := newTestRepository(t)
+	first := tr.commit("c1", []string{"a.txt"})
+	tr.commit("c2", []string{"a.txt"})
+	tr.checkout("feature", true)
+	tr.commit("c3", []string{"b.txt"})
+	feature := tr.commit("c4", []string{"b.txt"})
+	tr.checkout("master", false)
+	previous := tr.commit("c5", []string{"a.txt"})
+	tr.commit("c6", []string{"a.txt"})
+	head, err := tr.repository.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	merge := tr.commit("merge", nil, head.Hash(), feature)
+	tr.commit("c7", []string{"a.txt"})
+	repository := tr.getGitRepository()
+
+	tests := []struct {
+		name         string
+		lastRevision string
+		limit        int
+		subjects     []string
+	}{
+		{"without revision", "", 1, []string{"c7"}},
+		{"range", previous.String(), 100, []string{"c7", "merge", "c6", "c4", "c3"}},
+		{"range with limit", previous.String(), 2, []string{"c7", "merge"}},
+		{"range from merge", merge.String(), 100, []string{"c7"}},
+		{"range from branch", feature.String(), 100, []string{"c7", "merge", "c6", "c5"}},
+		{"range from first commit", first.String(), 100, []string{"c7", "merge", "c6", "c5", "c4", "c3", "c2"}},
+		{"range from head", "HEAD", 100, []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commits, err := repository.GetCommits(test.lastRevision, test.limit, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if subjects := getSubjects(commits); !reflect.DeepEqual(subjects, test.subjects) {
+				t.Errorf("GetCommits(%s, %d) = %v, want %v", test.lastRevision, test.limit, subjects, test.subjects)
+			}
+		})
+	}
+
+	t.Run("unknown revision", func(t *testing.T) {
+		if _, err := repository.GetCommits("0000000000000000000000000000000000000001", 1, nil); !errors.As(err, &RevisionRangeError{}) {
+			t.Errorf("GetCommits() error = %v, want RevisionRangeError", err)
+		}
+	})
+}
+
+func TestPathFilterMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  PathFilter
+		path    string
+		matches bool
+	}{
+		{"empty filter", PathFilter{}, "src/main.go", true},
+		{"directory", PathFilter{Include: []string{"src"}}, "src/main.go", true},
+		{"directory with slashes", PathFilter{Include: []string{"/src/"}}, "src/util/git.go", true},
+		{"directory prefix", PathFilter{Include: []string{"src"}}, "srcs/main.go", false},
+		{"file", PathFilter{Include: []string{"go.mod"}}, "go.mod", true},
+		{"other file", PathFilter{Include: []string{"go.mod"}}, "go.sum", false},
+		{"glob", PathFilter{Include: []string{"src/*.go"}}, "src/main.go", true},
+		{"glob in subdirectory", PathFilter{Include: []string{"src/*.go"}}, "src/util/git.go", false},
+		{"empty pattern", PathFilter{Include: []string{""}}, "src/main.go", true},
+		{"one of the includes", PathFilter{Include: []string{"docs", "src"}}, "src/main.go", true},
+		{"exclude", PathFilter{Exclude: []string{"docs"}}, "docs/README.md", false},
+		{"not excluded", PathFilter{Exclude: []string{"docs"}}, "src/main.go", true},
+		{"exclude takes precedence", PathFilter{Include: []string{"src"}, Exclude: []string{"src/*_test.go"}}, "src/main_test.go", false},
+		{"include with exclude", PathFilter{Include: []string{"src"}, Exclude: []string{"src/*_test.go"}}, "src/main.go", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if matches := test.filter.Matches(test.path); matches != test.matches {
+				t.Errorf("Matches(%s) = %t, want %t", test.path, matches, test.matches)
+			}
+		})
+	}
+}
+
+func TestPathFilterIsEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *PathFilter
+		isEmpty bool
+	}{
+		{"nil", nil, true},
+		{"empty", &PathFilter{}, true},
+		{"include", &PathFilter{Include: []string{"src"}}, false},
+		{"exclude", &PathFilter{Exclude: []string{"docs"}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if isEmpty := test.filter.IsEmpty(); isEmpty != test.isEmpty {
+				t.Errorf("IsEmpty() = %t, want %t", isEmpty, test.isEmpty)
+			}
+		})
+	}
+}
+
+func TestGetCommitsWithPathFilter(t *testing.T) {
+	tr := newTestRepository(t)
+	first := tr.commit("c1", []string{"src/main.go", "docs/README.md"})
+	tr.commit("c2", []string{"docs/README.md"})
+	tr.commit("c3", []string{"src/main.go"})
+	tr.commit("c4", []string{"src/main_test.go"})
+	tr.commit("c5", []string{"docs/README.md"})
+	repository := tr.getGitRepository()
+
+	tests := []struct {
+		name        string
+		filter      *PathFilter
+		subjects    []string
+		headMatches bool
+	}{
+		{"no filter", nil, []string{"c5", "c4", "c3", "c2"}, true},
+		{"include", &PathFilter{Include: []string{"src"}}, []string{"c4", "c3"}, false},
+		{"include and exclude", &PathFilter{Include: []string{"src"}, Exclude: []string{"src/*_test.go"}}, []string{"c3"}, false},
+		{"exclude", &PathFilter{Exclude: []string{"src"}}, []string{"c5", "c2"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commits, err := repository.GetCommits(first.String(), 100, test.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if subjects := getSubjects(commits); !reflect.DeepEqual(subjects, test.subjects) {
+				t.Errorf("GetCommits() = %v, want %v", subjects, test.subjects)
+			}
+			headMatches, err := repository.HeadMatches(test.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if headMatches != test.headMatches {
+				t.Errorf("HeadMatches() = %t, want %t", headMatches, test.headMatches)
+			}
+		})
+	}
+}
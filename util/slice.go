@@ -1,5 +1,7 @@
 package util
 
+import "strings"
+
 func RemoveDuplicate[T string | int](sliceList []T) []T {
 	allKeys := make(map[T]bool)
 	var list []T
@@ -11,3 +13,26 @@ func RemoveDuplicate[T string | int](sliceList []T) []T {
 	}
 	return list
 }
+
+// SplitList splits the comma separated list, leaving out empty entries.
+func SplitList(list string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// SplitKeyValues splits the comma separated list of key=value entries, leaving out entries without a key.
+func SplitKeyValues(list string) map[string]string {
+	values := map[string]string{}
+	for _, entry := range SplitList(list) {
+		key, value, _ := strings.Cut(entry, "=")
+		if key = strings.TrimSpace(key); key != "" {
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
@@ -20,3 +20,13 @@ func GetHttpsVcsUrl(vcsUrl string) string {
 	re := regexp.MustCompile(`^git@(.*):(.*)$`)
 	return re.ReplaceAllString(vcsUrl, "https://$1/$2")
 }
+
+// GetVcsRepositoryPath returns the repository path of the vcs url, for example owner/repo for git@github.com:owner/repo.git
+func GetVcsRepositoryPath(vcsUrl string) string {
+	re := regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?[^:/]+(?::[0-9]+)?[:/](.*?)(?:\.git)?/?$`)
+	matches := re.FindStringSubmatch(vcsUrl)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}